@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"prservice/internal/app"
 	"prservice/internal/config"
@@ -10,6 +12,14 @@ import (
 func main() {
 	cfg := config.Load()
 
+	// pr-service migrate up | down [N] | status — управление схемой без запуска сервиса
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(context.Background(), cfg, os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
 	if err := app.Run(cfg); err != nil {
 		log.Fatalf("app terminated: %v", err)
 	}
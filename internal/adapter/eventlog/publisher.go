@@ -0,0 +1,47 @@
+// Package eventlog — издатель доменных событий в журнал процесса: по строке JSON на событие.
+// Используется, пока события не нужно доставлять во внешние системы.
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type Publisher struct {
+	logger *log.Logger
+}
+
+func NewPublisher(w io.Writer) *Publisher {
+	return &Publisher{logger: log.New(w, "event ", log.LstdFlags)}
+}
+
+// eventJSON — представление события в журнале
+type eventJSON struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Team       string          `json:"team,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+func (p *Publisher) Publish(_ context.Context, e domain.Event) error {
+	line, err := json.Marshal(eventJSON{
+		ID:         e.ID,
+		Type:       string(e.Type),
+		Team:       string(e.Team),
+		OccurredAt: e.OccurredAt,
+		Payload:    e.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Println(string(line))
+	return nil
+}
+
+var _ domain.EventPublisher = (*Publisher)(nil)
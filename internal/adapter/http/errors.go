@@ -33,6 +33,94 @@ func writeError(w http.ResponseWriter, err error) {
 		resp.Error.Code = "NO_CANDIDATE"
 		resp.Error.Message = err.Error()
 		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidSettings):
+		resp.Error.Code = "INVALID_SETTINGS"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotEnoughReviewers):
+		resp.Error.Code = "NOT_ENOUGH_REVIEWERS"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrAllReviewersAtCapacity):
+		resp.Error.Code = "ALL_REVIEWERS_AT_CAPACITY"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidCapacity):
+		resp.Error.Code = "INVALID_CAPACITY"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidUnavailability):
+		resp.Error.Code = "INVALID_UNAVAILABILITY"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrEmptyBulkRequest):
+		resp.Error.Code = "INVALID_REQUEST"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidReviewState):
+		resp.Error.Code = "INVALID_REVIEW_STATE"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrApprovalQuorumNotMet):
+		resp.Error.Code = "APPROVAL_QUORUM_NOT_MET"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidTransition):
+		resp.Error.Code = "INVALID_TRANSITION"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrPRNotOpen):
+		resp.Error.Code = "PR_NOT_OPEN"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidFilter):
+		resp.Error.Code = "INVALID_FILTER"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotTeamMember):
+		resp.Error.Code = "NOT_TEAM_MEMBER"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidReviewsPolicy):
+		resp.Error.Code = "INVALID_REQUEST"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidDeleteMode):
+		resp.Error.Code = "INVALID_REQUEST"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrTeamHasOpenPRs):
+		resp.Error.Code = "TEAM_HAS_OPEN_PRS"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidFallbacks):
+		resp.Error.Code = "INVALID_FALLBACKS"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidCodeOwners):
+		resp.Error.Code = "INVALID_CODE_OWNERS"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		resp.Error.Code = "INVALID_WEBHOOK"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidSignature):
+		resp.Error.Code = "INVALID_SIGNATURE"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, domain.ErrUnknownIdentity):
+		resp.Error.Code = "UNKNOWN_IDENTITY"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidNotificationPreferences):
+		resp.Error.Code = "INVALID_NOTIFICATION_PREFERENCES"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidReviewSLA):
+		resp.Error.Code = "INVALID_REVIEW_SLA"
+		resp.Error.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
 	case errors.Is(err, domain.ErrNotFound):
 		resp.Error.Code = "NOT_FOUND"
 		resp.Error.Message = err.Error()
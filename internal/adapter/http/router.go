@@ -2,22 +2,36 @@ package httpadapter
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
 
 	"prservice/internal/adapter/http/api"
 )
 
-func NewRouter(server api.ServerInterface) http.Handler {
+// NewRouter — маршруты сервиса; ready — готовность принимать трафик (для /ready)
+func NewRouter(server api.ServerInterface, ready *atomic.Bool) http.Handler {
 	r := chi.NewRouter()
 
-	// healthcheck
+	// healthcheck: процесс жив
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	// readiness: false во время остановки, чтобы балансировщик перестал слать запросы
+	r.Get("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"shutting_down"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+
 	// все маршруты из OpenAPI
 	api.HandlerFromMux(server, r)
 
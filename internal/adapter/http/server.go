@@ -1,8 +1,12 @@
 package httpadapter
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"prservice/internal/adapter/http/api"
 	"prservice/internal/domain"
@@ -10,23 +14,43 @@ import (
 )
 
 type Server struct {
-	teamSvc *usecase.TeamService
-	userSvc *usecase.UserService
-	prSvc   *usecase.PRService
-	prRepo  domain.PRRepository
+	teamSvc    *usecase.TeamService
+	userSvc    *usecase.UserService
+	prSvc      *usecase.PRService
+	webhookSvc *usecase.WebhookService
+	vcsSvc     *usecase.VCSSyncService
+	notifySvc  *usecase.NotificationService
+	github     VCSParser
+	gitlab     VCSParser
+	prRepo     domain.PRRepository
+}
+
+// VCSParser проверяет подлинность входящего вебхука GitHub/GitLab и разбирает событие о PR;
+// nil без ошибки — событие не меняет PR
+type VCSParser interface {
+	Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error)
 }
 
 func NewServer(
 	team *usecase.TeamService,
 	user *usecase.UserService,
 	pr *usecase.PRService,
+	webhook *usecase.WebhookService,
+	vcs *usecase.VCSSyncService,
+	notify *usecase.NotificationService,
+	github, gitlab VCSParser,
 	prRepo domain.PRRepository,
 ) *Server {
 	return &Server{
-		teamSvc: team,
-		userSvc: user,
-		prSvc:   pr,
-		prRepo:  prRepo,
+		teamSvc:    team,
+		userSvc:    user,
+		prSvc:      pr,
+		webhookSvc: webhook,
+		vcsSvc:     vcs,
+		notifySvc:  notify,
+		github:     github,
+		gitlab:     gitlab,
+		prRepo:     prRepo,
 	}
 }
 
@@ -40,8 +64,18 @@ func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	dTeam := domain.Team{
-		Name:    domain.TeamName(req.TeamName),
-		Members: make([]domain.TeamMember, 0, len(req.Members)),
+		Name:     domain.TeamName(req.TeamName),
+		Members:  make([]domain.TeamMember, 0, len(req.Members)),
+		Settings: domain.DefaultTeamSettings(),
+	}
+	if req.MinReviewers != nil {
+		dTeam.Settings.MinReviewers = *req.MinReviewers
+	}
+	if req.MaxReviewers != nil {
+		dTeam.Settings.MaxReviewers = *req.MaxReviewers
+	}
+	if req.RequiredApprovals != nil {
+		dTeam.Settings.RequiredApprovals = *req.RequiredApprovals
 	}
 	for _, m := range req.Members {
 		dTeam.Members = append(dTeam.Members, domain.TeamMember{
@@ -57,17 +91,7 @@ func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := api.Team{
-		TeamName: string(res.Name),
-		Members:  make([]api.TeamMember, 0, len(res.Members)),
-	}
-	for _, m := range res.Members {
-		resp.Members = append(resp.Members, api.TeamMember{
-			UserId:   string(m.UserID),
-			Username: m.Username,
-			IsActive: m.IsActive,
-		})
-	}
+	resp := mapTeamToAPI(res)
 
 	writeJSON(w, http.StatusCreated, struct {
 		Team api.Team `json:"team"`
@@ -83,180 +107,1253 @@ func (s *Server) GetTeamGet(w http.ResponseWriter, r *http.Request, params api.G
 		return
 	}
 
-	resp := api.Team{
-		TeamName: string(res.Name),
-		Members:  make([]api.TeamMember, 0, len(res.Members)),
+	resp := mapTeamToAPI(res)
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ======== /team (DELETE) ========
+
+func (s *Server) DeleteTeam(w http.ResponseWriter, r *http.Request, params api.DeleteTeamParams) {
+	mode := domain.TeamDeleteArchive
+	if params.Mode != nil {
+		mode = domain.TeamDeleteMode(*params.Mode)
 	}
-	for _, m := range res.Members {
-		resp.Members = append(resp.Members, api.TeamMember{
-			UserId:   string(m.UserID),
+
+	if err := s.teamSvc.DeleteTeam(r.Context(), domain.TeamName(params.TeamName), mode); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		TeamName string `json:"team_name"`
+		Mode     string `json:"mode"`
+	}{TeamName: params.TeamName, Mode: string(mode)})
+}
+
+// ======== /team/setSettings (POST) ========
+
+func (s *Server) PostTeamSetSettings(w http.ResponseWriter, r *http.Request) {
+	var req api.TeamSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	current, err := s.teamSvc.GetSettings(r.Context(), domain.TeamName(req.TeamName))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	upd := *current
+	upd.MinReviewers = req.MinReviewers
+	upd.MaxReviewers = req.MaxReviewers
+	if req.RequiredApprovals != nil {
+		upd.RequiredApprovals = *req.RequiredApprovals
+	}
+
+	settings, err := s.teamSvc.UpdateSettings(r.Context(), domain.TeamName(req.TeamName), upd)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Settings api.TeamSettings `json:"settings"`
+	}{Settings: api.TeamSettings{
+		TeamName:          req.TeamName,
+		MinReviewers:      settings.MinReviewers,
+		MaxReviewers:      settings.MaxReviewers,
+		RequiredApprovals: &settings.RequiredApprovals,
+	}})
+}
+
+// ======== /team/setFallbacks (POST) ========
+
+func (s *Server) PostTeamSetFallbacks(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName      string   `json:"team_name"`
+		FallbackTeams []string `json:"fallback_teams"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	fallbacks := make([]domain.TeamName, len(req.FallbackTeams))
+	for i, f := range req.FallbackTeams {
+		fallbacks[i] = domain.TeamName(f)
+	}
+
+	res, err := s.teamSvc.SetFallbacks(r.Context(), domain.TeamName(req.TeamName), fallbacks)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Team api.Team `json:"team"`
+	}{Team: mapTeamToAPI(res)})
+}
+
+// ======== /team/setCodeOwners (POST) ========
+
+func (s *Server) PostTeamSetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req api.CodeOwners
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	owners := domain.CodeOwners{
+		Rules:  make([]domain.CodeOwnerRule, 0, len(req.Rules)),
+		Groups: make([]domain.CodeOwnerGroup, 0, len(req.Groups)),
+	}
+	for _, rule := range req.Rules {
+		owners.Rules = append(owners.Rules, domain.CodeOwnerRule{
+			Pattern: rule.Pattern,
+			Owners:  rule.Owners,
+		})
+	}
+	for _, g := range req.Groups {
+		members := make([]domain.UserID, len(g.Members))
+		for i, m := range g.Members {
+			members[i] = domain.UserID(m)
+		}
+		owners.Groups = append(owners.Groups, domain.CodeOwnerGroup{Name: g.Name, Members: members})
+	}
+
+	res, err := s.teamSvc.SetCodeOwners(r.Context(), domain.TeamName(req.TeamName), owners)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		CodeOwners api.CodeOwners `json:"code_owners"`
+	}{CodeOwners: mapCodeOwnersToAPI(domain.TeamName(req.TeamName), res)})
+}
+
+// ======== /team/getCodeOwners (GET) ========
+
+func (s *Server) GetTeamGetCodeOwners(w http.ResponseWriter, r *http.Request, params api.GetTeamGetCodeOwnersParams) {
+	res, err := s.teamSvc.GetCodeOwners(r.Context(), domain.TeamName(params.TeamName))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapCodeOwnersToAPI(domain.TeamName(params.TeamName), res))
+}
+
+// ======== /team/setReviewSLA (POST) ========
+
+func (s *Server) PostTeamSetReviewSLA(w http.ResponseWriter, r *http.Request) {
+	var req api.ReviewSLA
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sla := domain.ReviewSLA{
+		Hours:  req.SlaHours,
+		Policy: domain.SLAPolicy(req.Policy),
+	}
+	if req.LeadId != nil {
+		sla.LeadID = domain.UserID(*req.LeadId)
+	}
+
+	res, err := s.teamSvc.SetReviewSLA(r.Context(), domain.TeamName(req.TeamName), sla)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		ReviewSLA api.ReviewSLA `json:"review_sla"`
+	}{ReviewSLA: mapReviewSLAToAPI(domain.TeamName(req.TeamName), res)})
+}
+
+// ======== /team/getReviewSLA (GET) ========
+
+func (s *Server) GetTeamGetReviewSLA(w http.ResponseWriter, r *http.Request, params api.GetTeamGetReviewSLAParams) {
+	res, err := s.teamSvc.GetReviewSLA(r.Context(), domain.TeamName(params.TeamName))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapReviewSLAToAPI(domain.TeamName(params.TeamName), res))
+}
+
+// ======== /team/addMembers (POST) ========
+
+func (s *Server) PostTeamAddMembers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string           `json:"team_name"`
+		Members  []api.TeamMember `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	members := make([]domain.TeamMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		members = append(members, domain.TeamMember{
+			UserID:   domain.UserID(m.UserId),
 			Username: m.Username,
 			IsActive: m.IsActive,
 		})
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	res, err := s.teamSvc.AddMembers(r.Context(), domain.TeamName(req.TeamName), members)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Team api.Team `json:"team"`
+	}{Team: mapTeamToAPI(res)})
+}
+
+// ======== /team/removeMember (POST) ========
+
+func (s *Server) PostTeamRemoveMember(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName    string `json:"team_name"`
+		UserId      string `json:"user_id"`
+		OpenReviews string `json:"open_reviews"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	u, report, err := s.teamSvc.RemoveMember(
+		r.Context(),
+		domain.TeamName(req.TeamName),
+		domain.UserID(req.UserId),
+		domain.OpenReviewsPolicy(req.OpenReviews),
+	)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapTeamMemberChangeToAPI(u, report))
+}
+
+// ======== /team/moveMember (POST) ========
+
+func (s *Server) PostTeamMoveMember(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserId      string `json:"user_id"`
+		FromTeam    string `json:"from_team"`
+		ToTeam      string `json:"to_team"`
+		OpenReviews string `json:"open_reviews"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	u, report, err := s.teamSvc.MoveMember(
+		r.Context(),
+		domain.UserID(req.UserId),
+		domain.TeamName(req.FromTeam),
+		domain.TeamName(req.ToTeam),
+		domain.OpenReviewsPolicy(req.OpenReviews),
+	)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapTeamMemberChangeToAPI(u, report))
 }
 
 // ======== /users/setIsActive (POST) ========
 
 func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserId   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		UserId              string `json:"user_id"`
+		IsActive            bool   `json:"is_active"`
+		ReassignOpenReviews bool   `json:"reassign_open_reviews"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
+	if !req.IsActive && req.ReassignOpenReviews {
+		u, report, err := s.userSvc.DeactivateAndReassign(r.Context(), domain.UserID(req.UserId))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		respReport := mapReassignmentReportToAPI(report)
+		writeJSON(w, http.StatusOK, struct {
+			User         api.User                `json:"user"`
+			Reassignment *api.ReassignmentReport `json:"reassignment"`
+		}{User: mapUserToAPI(u), Reassignment: &respReport})
+		return
+	}
+
 	u, err := s.userSvc.SetIsActive(r.Context(), domain.UserID(req.UserId), req.IsActive)
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
-	respUser := api.User{
-		UserId:   string(u.ID),
-		Username: u.Username,
-		TeamName: string(u.TeamName),
-		IsActive: u.IsActive,
-	}
+	respUser := mapUserToAPI(u)
 
 	writeJSON(w, http.StatusOK, struct {
 		User api.User `json:"user"`
 	}{User: respUser})
 }
 
-// ======== /pullRequest/create (POST) ========
+// ======== /users/bulkDeactivate (POST) ========
 
-func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostUsersBulkDeactivate(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestId   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorId        string `json:"author_id"`
+		TeamName string   `json:"team_name"`
+		UserIds  []string `json:"user_ids"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
-	dPR := domain.PullRequest{
-		ID:       domain.PullRequestID(req.PullRequestId),
-		Name:     req.PullRequestName,
-		AuthorID: domain.UserID(req.AuthorId),
+	ids := make([]domain.UserID, 0, len(req.UserIds))
+	for _, id := range req.UserIds {
+		ids = append(ids, domain.UserID(id))
 	}
 
-	pr, err := s.prSvc.CreatePR(r.Context(), dPR)
+	users, report, err := s.userSvc.BulkDeactivate(r.Context(), domain.TeamName(req.TeamName), ids)
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
-	resp := mapPRToAPI(pr)
-	writeJSON(w, http.StatusCreated, struct {
-		Pr api.PullRequest `json:"pr"`
-	}{Pr: resp})
+	resp := struct {
+		Users        []api.User             `json:"users"`
+		Reassignment api.ReassignmentReport `json:"reassignment"`
+	}{
+		Users:        make([]api.User, 0, len(users)),
+		Reassignment: mapReassignmentReportToAPI(report),
+	}
+	for i := range users {
+		resp.Users = append(resp.Users, mapUserToAPI(&users[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
 }
 
-// ======== /pullRequest/merge (POST) ========
+// ======== /users/setMaxOpenReviews (POST) ========
 
-func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostUsersSetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestId string `json:"pull_request_id"`
+		UserId         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
-	pr, err := s.prSvc.Merge(r.Context(), domain.PullRequestID(req.PullRequestId))
+	u, err := s.userSvc.SetMaxOpenReviews(r.Context(), domain.UserID(req.UserId), req.MaxOpenReviews)
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
-	resp := mapPRToAPI(pr)
 	writeJSON(w, http.StatusOK, struct {
-		Pr api.PullRequest `json:"pr"`
-	}{Pr: resp})
+		User api.User `json:"user"`
+	}{User: mapUserToAPI(u)})
 }
 
-// ======== /pullRequest/reassign (POST) ========
+// ======== /users/addUnavailability (POST) ========
 
-func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostUsersAddUnavailability(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestId string `json:"pull_request_id"`
-		OldUserId     string `json:"old_user_id"`
+		UserId   string    `json:"user_id"`
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+		Reason   string    `json:"reason"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
-	pr, newRev, err := s.prSvc.ReassignReviewer(
-		r.Context(),
-		domain.PullRequestID(req.PullRequestId),
-		domain.UserID(req.OldUserId),
-	)
+	u, err := s.userSvc.AddUnavailability(r.Context(), domain.Unavailability{
+		UserID:   domain.UserID(req.UserId),
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   domain.UnavailabilityReason(req.Reason),
+	})
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
-	resp := mapPRToAPI(pr)
-	writeJSON(w, http.StatusOK, struct {
-		Pr         api.PullRequest `json:"pr"`
-		ReplacedBy string          `json:"replaced_by"`
-	}{Pr: resp, ReplacedBy: string(newRev)})
+	writeJSON(w, http.StatusCreated, struct {
+		Unavailability api.Unavailability `json:"unavailability"`
+	}{Unavailability: mapUnavailabilityToAPI(u)})
 }
 
-// ======== /users/getReview (GET) ========
+// ======== /users/getUnavailability (GET) ========
 
-func (s *Server) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params api.GetUsersGetReviewParams) {
-	prs, err := s.prSvc.ListByReviewer(r.Context(), domain.UserID(params.UserId))
+func (s *Server) GetUsersGetUnavailability(w http.ResponseWriter, r *http.Request, params api.GetUsersGetUnavailabilityParams) {
+	list, err := s.userSvc.ListUnavailability(r.Context(), domain.UserID(params.UserId))
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
 	resp := struct {
-		UserId       string                 `json:"user_id"`
-		PullRequests []api.PullRequestShort `json:"pull_requests"`
+		UserId         string               `json:"user_id"`
+		Unavailability []api.Unavailability `json:"unavailability"`
 	}{
-		UserId:       params.UserId,
-		PullRequests: make([]api.PullRequestShort, 0, len(prs)),
+		UserId:         params.UserId,
+		Unavailability: make([]api.Unavailability, 0, len(list)),
 	}
-
-	for _, pr := range prs {
-		resp.PullRequests = append(resp.PullRequests, api.PullRequestShort{
-			PullRequestId:   string(pr.ID),
-			PullRequestName: pr.Name,
-			AuthorId:        string(pr.AuthorID),
-			Status:         api.PullRequestShortStatus(pr.Status),
-		})
+	for i := range list {
+		resp.Unavailability = append(resp.Unavailability, mapUnavailabilityToAPI(&list[i]))
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
-// ======== helpers ========
+// ======== /users/deleteUnavailability (POST) ========
 
-func mapPRToAPI(pr *domain.PullRequest) api.PullRequest {
-	resp := api.PullRequest{
-		PullRequestId:   string(pr.ID),
-		PullRequestName: pr.Name,
-		AuthorId:        string(pr.AuthorID),
-		Status:         api.PullRequestStatus(pr.Status),
-		AssignedReviewers: func() []string {
-			res := make([]string, len(pr.AssignedReviewers))
-			for i, id := range pr.AssignedReviewers {
-				res[i] = string(id)
-			}
-			return res
-		}(),
-		CreatedAt: pr.CreatedAt,
-		MergedAt:  pr.MergedAt,
+func (s *Server) PostUsersDeleteUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Id int64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	u, err := s.userSvc.DeleteUnavailability(r.Context(), domain.UnavailabilityID(req.Id))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Unavailability api.Unavailability `json:"unavailability"`
+	}{Unavailability: mapUnavailabilityToAPI(u)})
+}
+
+// ======== /users/getNotificationPreferences (GET) ========
+
+func (s *Server) GetUsersGetNotificationPreferences(
+	w http.ResponseWriter,
+	r *http.Request,
+	params api.GetUsersGetNotificationPreferencesParams,
+) {
+	p, err := s.notifySvc.GetPreferences(r.Context(), domain.UserID(params.UserId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.NotificationPreferencesResponse{Preferences: mapPreferencesToAPI(p)})
+}
+
+// ======== /users/setNotificationPreferences (POST) ========
+
+func (s *Server) PostUsersSetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var req api.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	p := domain.NotificationPreferences{
+		UserID:   domain.UserID(req.UserId),
+		Channels: make([]domain.NotificationChannel, len(req.Channels)),
+		Events:   mapEventTypesFromAPI(&req.Events),
+	}
+	for i, c := range req.Channels {
+		p.Channels[i] = domain.NotificationChannel(c)
+	}
+	if req.Email != nil {
+		p.Email = *req.Email
+	}
+	if req.ChatHandle != nil {
+		p.ChatHandle = *req.ChatHandle
+	}
+
+	res, err := s.notifySvc.SetPreferences(r.Context(), p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.NotificationPreferencesResponse{Preferences: mapPreferencesToAPI(res)})
+}
+
+// ======== /pullRequest/create (POST) ========
+
+func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId   string   `json:"pull_request_id"`
+		PullRequestName string   `json:"pull_request_name"`
+		AuthorId        string   `json:"author_id"`
+		TeamName        string   `json:"team_name"`
+		ChangedFiles    []string `json:"changed_files"`
+		Draft           bool     `json:"draft"`
+		Seed            *int64   `json:"seed"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	dPR := domain.PullRequest{
+		ID:           domain.PullRequestID(req.PullRequestId),
+		Name:         req.PullRequestName,
+		AuthorID:     domain.UserID(req.AuthorId),
+		TeamName:     domain.TeamName(req.TeamName),
+		ChangedFiles: req.ChangedFiles,
+	}
+	if req.Draft {
+		dPR.Status = domain.PRStatusDraft
+	}
+
+	pr, err := s.prSvc.CreatePR(r.Context(), dPR, req.Seed)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := mapPRToAPI(pr)
+	writeJSON(w, http.StatusCreated, struct {
+		Pr api.PullRequest `json:"pr"`
+	}{Pr: resp})
+}
+
+// ======== /pullRequest/merge (POST) ========
+
+func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+		Force         bool   `json:"force"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := s.prSvc.Merge(r.Context(), domain.PullRequestID(req.PullRequestId), req.Force)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := mapPRToAPI(pr)
+	writeJSON(w, http.StatusOK, struct {
+		Pr api.PullRequest `json:"pr"`
+	}{Pr: resp})
+}
+
+// ======== /pullRequest/get (GET) ========
+
+func (s *Server) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params api.GetPullRequestGetParams) {
+	pr, err := s.prSvc.GetPR(r.Context(), domain.PullRequestID(params.PullRequestId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.PullRequestResponse{Pr: mapPRToAPI(pr)})
+}
+
+// ======== /pullRequest/assignmentTrace (GET) ========
+
+func (s *Server) GetPullRequestAssignmentTrace(
+	w http.ResponseWriter,
+	r *http.Request,
+	params api.GetPullRequestAssignmentTraceParams,
+) {
+	traces, err := s.prSvc.GetAssignmentTraces(r.Context(), domain.PullRequestID(params.PullRequestId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	res := make([]api.AssignmentTrace, len(traces))
+	for i, t := range traces {
+		res[i] = mapAssignmentTraceToAPI(t)
+	}
+	writeJSON(w, http.StatusOK, struct {
+		PullRequestID string                `json:"pull_request_id"`
+		Traces        []api.AssignmentTrace `json:"traces"`
+	}{PullRequestID: params.PullRequestId, Traces: res})
+}
+
+// ======== /pullRequest/list (GET) ========
+
+func (s *Server) GetPullRequestList(w http.ResponseWriter, r *http.Request, params api.GetPullRequestListParams) {
+	filter := domain.PRFilter{
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		MergedFrom:  params.MergedFrom,
+		MergedTo:    params.MergedTo,
+	}
+	if params.Status != nil {
+		filter.Status = domain.PRStatus(*params.Status)
+	}
+	if params.AuthorId != nil {
+		filter.AuthorID = domain.UserID(*params.AuthorId)
+	}
+	if params.TeamName != nil {
+		filter.TeamName = domain.TeamName(*params.TeamName)
+	}
+	if params.ReviewerId != nil {
+		filter.ReviewerID = domain.UserID(*params.ReviewerId)
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	prs, next, err := s.prSvc.ListPRs(r.Context(), filter, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := struct {
+		PullRequests []api.PullRequest `json:"pull_requests"`
+		NextCursor   *string           `json:"next_cursor"`
+	}{
+		PullRequests: make([]api.PullRequest, 0, len(prs)),
+	}
+	for i := range prs {
+		resp.PullRequests = append(resp.PullRequests, mapPRToAPI(&prs[i]))
+	}
+	if next != "" {
+		resp.NextCursor = &next
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ======== /pullRequest/markReady (POST) ========
+
+func (s *Server) PostPullRequestMarkReady(w http.ResponseWriter, r *http.Request) {
+	s.handlePRTransition(w, r, s.prSvc.MarkReady)
+}
+
+// ======== /pullRequest/close (POST) ========
+
+func (s *Server) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+	s.handlePRTransition(w, r, s.prSvc.Close)
+}
+
+// ======== /pullRequest/reopen (POST) ========
+
+func (s *Server) PostPullRequestReopen(w http.ResponseWriter, r *http.Request) {
+	s.handlePRTransition(w, r, s.prSvc.Reopen)
+}
+
+// ======== /pullRequest/submitReview (POST) ========
+
+func (s *Server) PostPullRequestSubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+		ReviewerId    string `json:"reviewer_id"`
+		State         string `json:"state"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := s.prSvc.SubmitReview(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestId),
+		domain.UserID(req.ReviewerId),
+		domain.ReviewState(req.State),
+	)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := mapPRToAPI(pr)
+	writeJSON(w, http.StatusOK, struct {
+		Pr api.PullRequest `json:"pr"`
+	}{Pr: resp})
+}
+
+// ======== /pullRequest/reassign (POST) ========
+
+func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+		OldUserId     string `json:"old_user_id"`
+		Seed          *int64 `json:"seed"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	pr, newRev, err := s.prSvc.ReassignReviewer(
+		r.Context(),
+		domain.PullRequestID(req.PullRequestId),
+		domain.UserID(req.OldUserId),
+		req.Seed,
+	)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := mapPRToAPI(pr)
+	writeJSON(w, http.StatusOK, struct {
+		Pr         api.PullRequest `json:"pr"`
+		ReplacedBy string          `json:"replaced_by"`
+	}{Pr: resp, ReplacedBy: string(newRev)})
+}
+
+// ======== /users/getReview (GET) ========
+
+func (s *Server) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params api.GetUsersGetReviewParams) {
+	prs, err := s.prSvc.ListByReviewer(r.Context(), domain.UserID(params.UserId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := struct {
+		UserId       string                 `json:"user_id"`
+		PullRequests []api.PullRequestShort `json:"pull_requests"`
+	}{
+		UserId:       params.UserId,
+		PullRequests: make([]api.PullRequestShort, 0, len(prs)),
+	}
+
+	for _, pr := range prs {
+		resp.PullRequests = append(resp.PullRequests, api.PullRequestShort{
+			PullRequestId:   string(pr.ID),
+			PullRequestName: pr.Name,
+			AuthorId:        string(pr.AuthorID),
+			Status:         api.PullRequestShortStatus(pr.Status),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ======== /webhook/create (POST) ========
+
+func (s *Server) PostWebhookCreate(w http.ResponseWriter, r *http.Request) {
+	var req api.PostWebhookCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := s.webhookSvc.CreateSubscription(r.Context(), domain.WebhookSubscription{
+		Team:   domain.TeamName(req.TeamName),
+		URL:    req.Url,
+		Secret: req.Secret,
+		Events: mapEventTypesFromAPI(req.Events),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, api.WebhookResponse{Webhook: mapWebhookToAPI(sub)})
+}
+
+// ======== /webhook/get (GET) ========
+
+func (s *Server) GetWebhookGet(w http.ResponseWriter, r *http.Request, params api.GetWebhookGetParams) {
+	sub, err := s.webhookSvc.GetSubscription(r.Context(), domain.WebhookID(params.WebhookId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.WebhookResponse{Webhook: mapWebhookToAPI(sub)})
+}
+
+// ======== /webhook/list (GET) ========
+
+func (s *Server) GetWebhookList(w http.ResponseWriter, r *http.Request, params api.GetWebhookListParams) {
+	var team domain.TeamName
+	if params.TeamName != nil {
+		team = domain.TeamName(*params.TeamName)
+	}
+
+	subs, err := s.webhookSvc.ListSubscriptions(r.Context(), team)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := struct {
+		Webhooks []api.WebhookSubscription `json:"webhooks"`
+	}{Webhooks: make([]api.WebhookSubscription, 0, len(subs))}
+	for i := range subs {
+		resp.Webhooks = append(resp.Webhooks, mapWebhookToAPI(&subs[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ======== /webhook/update (POST) ========
+
+func (s *Server) PostWebhookUpdate(w http.ResponseWriter, r *http.Request) {
+	var req api.PostWebhookUpdateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	sub := domain.WebhookSubscription{
+		ID:     domain.WebhookID(req.WebhookId),
+		URL:    req.Url,
+		Events: mapEventTypesFromAPI(req.Events),
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret
+	}
+
+	res, err := s.webhookSvc.UpdateSubscription(r.Context(), sub)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.WebhookResponse{Webhook: mapWebhookToAPI(res)})
+}
+
+// ======== /webhook (DELETE) ========
+
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request, params api.DeleteWebhookParams) {
+	if err := s.webhookSvc.DeleteSubscription(r.Context(), domain.WebhookID(params.WebhookId)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		WebhookId int64 `json:"webhook_id"`
+	}{WebhookId: params.WebhookId})
+}
+
+// ======== /webhook/deliveries (GET) ========
+
+func (s *Server) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request, params api.GetWebhookDeliveriesParams) {
+	var filter domain.WebhookDeliveryFilter
+	if params.WebhookId != nil {
+		filter.SubscriptionID = domain.WebhookID(*params.WebhookId)
+	}
+	if params.Status != nil {
+		filter.Status = domain.DeliveryStatus(*params.Status)
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+
+	list, err := s.webhookSvc.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := struct {
+		Deliveries []api.WebhookDelivery `json:"deliveries"`
+	}{Deliveries: make([]api.WebhookDelivery, 0, len(list))}
+	for i := range list {
+		resp.Deliveries = append(resp.Deliveries, mapDeliveryToAPI(&list[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ======== /webhook/redeliver (POST) ========
+
+func (s *Server) PostWebhookRedeliver(w http.ResponseWriter, r *http.Request) {
+	var req api.PostWebhookRedeliverJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	d, err := s.webhookSvc.Redeliver(r.Context(), req.DeliveryId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Delivery api.WebhookDelivery `json:"delivery"`
+	}{Delivery: mapDeliveryToAPI(d)})
+}
+
+// ======== /vcs/github, /vcs/gitlab (POST) ========
+
+// maxVCSBody — ограничение размера тела входящего вебхука
+const maxVCSBody = 5 << 20
+
+func (s *Server) PostVcsGithub(w http.ResponseWriter, r *http.Request) {
+	s.handleVCSEvent(w, r, s.github)
+}
+
+func (s *Server) PostVcsGitlab(w http.ResponseWriter, r *http.Request) {
+	s.handleVCSEvent(w, r, s.gitlab)
+}
+
+// ======== helpers ========
+
+// handlePRTransition — общий обработчик смены статуса PR по pull_request_id
+func (s *Server) handlePRTransition(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error),
+) {
+	var req api.PullRequestIdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := transition(r.Context(), domain.PullRequestID(req.PullRequestId))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.PullRequestResponse{Pr: mapPRToAPI(pr)})
+}
+
+// handleVCSEvent — проверка, разбор и применение входящего вебхука GitHub/GitLab
+func (s *Server) handleVCSEvent(w http.ResponseWriter, r *http.Request, parser VCSParser) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxVCSBody))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	event, err := parser.Parse(r.Header, body)
+	if errors.Is(err, domain.ErrInvalidSignature) {
+		writeError(w, err)
+		return
+	}
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	resp := api.VCSEventResponse{Status: api.IGNORED}
+	if event != nil {
+		pr, err := s.vcsSvc.Apply(r.Context(), *event)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if pr != nil {
+			apiPR := mapPRToAPI(pr)
+			resp.Status = api.APPLIED
+			resp.Pr = &apiPR
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func mapTeamToAPI(team *domain.Team) api.Team {
+	resp := api.Team{
+		TeamName:          string(team.Name),
+		Members:           make([]api.TeamMember, 0, len(team.Members)),
+		MinReviewers:      &team.Settings.MinReviewers,
+		MaxReviewers:      &team.Settings.MaxReviewers,
+		RequiredApprovals: &team.Settings.RequiredApprovals,
+	}
+	for _, m := range team.Members {
+		resp.Members = append(resp.Members, api.TeamMember{
+			UserId:   string(m.UserID),
+			Username: m.Username,
+			IsActive: m.IsActive,
+		})
+	}
+	if len(team.Fallbacks) > 0 {
+		fallbacks := make([]string, len(team.Fallbacks))
+		for i, f := range team.Fallbacks {
+			fallbacks[i] = string(f)
+		}
+		resp.FallbackTeams = &fallbacks
+	}
+	return resp
+}
+
+func mapReviewSLAToAPI(team domain.TeamName, sla *domain.ReviewSLA) api.ReviewSLA {
+	res := api.ReviewSLA{
+		TeamName: string(team),
+		SlaHours: sla.Hours,
+		Policy:   api.ReviewSLAPolicy(sla.Policy),
+	}
+	if sla.LeadID != "" {
+		lead := string(sla.LeadID)
+		res.LeadId = &lead
+	}
+	return res
+}
+
+func mapCodeOwnersToAPI(team domain.TeamName, owners *domain.CodeOwners) api.CodeOwners {
+	resp := api.CodeOwners{
+		TeamName: string(team),
+		Rules:    make([]api.CodeOwnerRule, 0, len(owners.Rules)),
+		Groups:   make([]api.CodeOwnerGroup, 0, len(owners.Groups)),
+	}
+	for _, rule := range owners.Rules {
+		resp.Rules = append(resp.Rules, api.CodeOwnerRule{Pattern: rule.Pattern, Owners: rule.Owners})
+	}
+	for _, g := range owners.Groups {
+		members := make([]string, len(g.Members))
+		for i, m := range g.Members {
+			members[i] = string(m)
+		}
+		resp.Groups = append(resp.Groups, api.CodeOwnerGroup{Name: g.Name, Members: members})
+	}
+	return resp
+}
+
+func mapUserToAPI(u *domain.User) api.User {
+	return api.User{
+		UserId:         string(u.ID),
+		Username:       u.Username,
+		TeamName:       string(u.TeamName),
+		IsActive:       u.IsActive,
+		MaxOpenReviews: u.MaxOpenReviews,
+	}
+}
+
+func mapTeamMemberChangeToAPI(u *domain.User, report *domain.ReassignmentReport) api.TeamMemberChange {
+	resp := api.TeamMemberChange{User: mapUserToAPI(u)}
+	if len(report.Reassigned) > 0 || len(report.NoCandidate) > 0 {
+		respReport := mapReassignmentReportToAPI(report)
+		resp.Reassignment = &respReport
+	}
+	return resp
+}
+
+func mapUnavailabilityToAPI(u *domain.Unavailability) api.Unavailability {
+	return api.Unavailability{
+		Id:       int64(u.ID),
+		UserId:   string(u.UserID),
+		StartsAt: u.StartsAt,
+		EndsAt:   u.EndsAt,
+		Reason:   api.UnavailabilityReason(u.Reason),
+	}
+}
+
+func mapPreferencesToAPI(p *domain.NotificationPreferences) api.NotificationPreferences {
+	resp := api.NotificationPreferences{
+		UserId:   string(p.UserID),
+		Channels: make([]api.NotificationChannel, len(p.Channels)),
+		Events:   make([]api.EventType, len(p.Events)),
+	}
+	for i, c := range p.Channels {
+		resp.Channels[i] = api.NotificationChannel(c)
+	}
+	for i, t := range p.Events {
+		resp.Events[i] = api.EventType(t)
+	}
+	if p.Email != "" {
+		email := p.Email
+		resp.Email = &email
+	}
+	if p.ChatHandle != "" {
+		handle := p.ChatHandle
+		resp.ChatHandle = &handle
+	}
+	return resp
+}
+
+func mapReassignmentReportToAPI(report *domain.ReassignmentReport) api.ReassignmentReport {
+	mapList := func(list []domain.ReviewReassignment) []api.ReviewReassignment {
+		res := make([]api.ReviewReassignment, 0, len(list))
+		for _, c := range list {
+			item := api.ReviewReassignment{
+				PullRequestId: string(c.PullRequestID),
+				OldUserId:     string(c.OldReviewerID),
+			}
+			if c.NewReviewerID != "" {
+				newID := string(c.NewReviewerID)
+				item.NewUserId = &newID
+			}
+			res = append(res, item)
+		}
+		return res
+	}
+
+	return api.ReassignmentReport{
+		Reassigned:  mapList(report.Reassigned),
+		NoCandidate: mapList(report.NoCandidate),
+	}
+}
+
+func mapAssignmentsToAPI(assignments []domain.ReviewerAssignment) []api.ReviewerAssignment {
+	res := make([]api.ReviewerAssignment, len(assignments))
+	for i, a := range assignments {
+		res[i] = api.ReviewerAssignment{
+			ReviewerId: string(a.ReviewerID),
+			Reason:     api.ReviewerAssignmentReason(a.Reason),
+			TeamName:   string(a.Team),
+		}
+		if len(a.Paths) > 0 {
+			paths := a.Paths
+			res[i].Paths = &paths
+		}
+	}
+	return res
+}
+
+func mapAssignmentTraceToAPI(t domain.AssignmentTrace) api.AssignmentTrace {
+	res := api.AssignmentTrace{
+		Kind:      api.AssignmentTraceKind(t.Kind),
+		Seed:      t.Seed,
+		Selected:  mapAssignmentsToAPI(t.Selected),
+		Teams:     make([]api.TeamTrace, len(t.Teams)),
+		CreatedAt: t.CreatedAt,
+	}
+	if t.ReplacedReviewer != "" {
+		replaced := string(t.ReplacedReviewer)
+		res.ReplacedReviewerId = &replaced
+	}
+	for i, team := range t.Teams {
+		tt := api.TeamTrace{
+			TeamName:   string(team.Team),
+			Strategy:   string(team.Strategy),
+			Candidates: make([]string, len(team.Candidates)),
+			Excluded:   make([]api.ExcludedCandidate, len(team.Excluded)),
+		}
+		for j, id := range team.Candidates {
+			tt.Candidates[j] = string(id)
+		}
+		if team.RotationPosition != "" {
+			position := string(team.RotationPosition)
+			tt.RotationPosition = &position
+		}
+		for j, e := range team.Excluded {
+			tt.Excluded[j] = api.ExcludedCandidate{
+				UserId: string(e.UserID),
+				Reason: api.ExcludedCandidateReason(e.Reason),
+			}
+		}
+		res.Teams[i] = tt
+	}
+	return res
+}
+
+func mapPRToAPI(pr *domain.PullRequest) api.PullRequest {
+	resp := api.PullRequest{
+		PullRequestId:   string(pr.ID),
+		PullRequestName: pr.Name,
+		AuthorId:        string(pr.AuthorID),
+		Status:         api.PullRequestStatus(pr.Status),
+		AssignedReviewers: func() []string {
+			res := make([]string, len(pr.AssignedReviewers))
+			for i, id := range pr.AssignedReviewers {
+				res[i] = string(id)
+			}
+			return res
+		}(),
+		CreatedAt: pr.CreatedAt,
+		MergedAt:  pr.MergedAt,
+		ClosedAt:  pr.ClosedAt,
+	}
+	if pr.TeamName != "" {
+		team := string(pr.TeamName)
+		resp.TeamName = &team
+	}
+	if pr.Assignments != nil {
+		assignment := mapAssignmentsToAPI(pr.Assignments)
+		resp.Assignment = &assignment
+	}
+	if pr.Reviews != nil {
+		reviews := make([]api.Review, len(pr.Reviews))
+		for i, rv := range pr.Reviews {
+			reviews[i] = api.Review{
+				ReviewerId:   string(rv.ReviewerID),
+				State:        api.ReviewState(rv.State),
+				SubmittedAt:  rv.SubmittedAt,
+				FirstActedAt: rv.FirstActedAt,
+				OverdueAt:    rv.OverdueAt,
+			}
+			if !rv.AssignedAt.IsZero() {
+				assignedAt := rv.AssignedAt
+				reviews[i].AssignedAt = &assignedAt
+			}
+		}
+		resp.Reviews = &reviews
+	}
+	return resp
+}
+
+func mapEventTypesFromAPI(types *[]api.EventType) []domain.EventType {
+	if types == nil {
+		return nil
+	}
+	res := make([]domain.EventType, len(*types))
+	for i, t := range *types {
+		res[i] = domain.EventType(t)
+	}
+	return res
+}
+
+func mapWebhookToAPI(sub *domain.WebhookSubscription) api.WebhookSubscription {
+	events := make([]api.EventType, len(sub.Events))
+	for i, t := range sub.Events {
+		events[i] = api.EventType(t)
+	}
+	return api.WebhookSubscription{
+		WebhookId: int64(sub.ID),
+		TeamName:  string(sub.Team),
+		Url:       sub.URL,
+		Events:    events,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+func mapDeliveryToAPI(d *domain.WebhookDelivery) api.WebhookDelivery {
+	resp := api.WebhookDelivery{
+		DeliveryId:    d.ID,
+		WebhookId:     int64(d.SubscriptionID),
+		EventId:       d.Event.ID,
+		EventType:     api.EventType(d.Event.Type),
+		Status:        api.WebhookDeliveryStatus(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+	// полезная нагрузка — JSON, который сервис сам и сформировал
+	_ = json.Unmarshal(d.Event.Payload, &resp.Payload)
+	if d.ResponseStatus != 0 {
+		status := d.ResponseStatus
+		resp.ResponseStatus = &status
+	}
+	if d.LastError != "" {
+		lastErr := d.LastError
+		resp.LastError = &lastErr
 	}
 	return resp
 }
@@ -0,0 +1,59 @@
+// Package notify — каналы отправки уведомлений пользователям.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// Chat — сообщения во входящий вебхук чата. Формат {"text": ...} понимают и Slack, и Mattermost.
+type Chat struct {
+	url    string
+	client *http.Client
+}
+
+func NewChat(url string, timeout time.Duration) *Chat {
+	return &Chat{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+func (c *Chat) Send(ctx context.Context, n domain.Notification) error {
+	text := fmt.Sprintf("%s %s", n.To, n.Subject)
+	if n.Body != "" {
+		text += "\n" + n.Body
+	}
+	body, err := json.Marshal(chatMessage{Text: text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("chat: unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var _ domain.Notifier = (*Chat)(nil)
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+var testNotification = domain.Notification{
+	To:      "bob@example.com",
+	Subject: "Вас назначили ревьювером pr-1",
+	Body:    "PR «Fix login» ждёт вашего ревью.",
+}
+
+func TestChatSend(t *testing.T) {
+	got := make(chan chatMessage, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg chatMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		got <- msg
+	}))
+	defer srv.Close()
+
+	n := testNotification
+	n.To = "@bob"
+	if err := NewChat(srv.URL, time.Second).Send(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-got; msg.Text != "@bob Вас назначили ревьювером pr-1\nPR «Fix login» ждёт вашего ревью." {
+		t.Errorf("text = %q", msg.Text)
+	}
+}
+
+func TestChatSendRejectsNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	if err := NewChat(srv.URL, time.Second).Send(context.Background(), testNotification); err == nil {
+		t.Fatal("expected error on 502")
+	}
+}
+
+// serveSMTP — минимальный SMTP-сервер на одно соединение; возвращает адрес, канал с аргументом
+// MAIL FROM и канал с DATA письма
+func serveSMTP(t *testing.T) (string, <-chan string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	from := make(chan string, 1)
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 test ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 test")
+			case "MAIL":
+				from <- strings.SplitN(line, ":", 2)[1]
+				_ = tp.PrintfLine("250 ok")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				msg, _ := tp.ReadDotBytes()
+				data <- string(msg)
+				_ = tp.PrintfLine("250 ok")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), from, data
+}
+
+func TestSMTPSend(t *testing.T) {
+	addr, from, data := serveSMTP(t)
+
+	s, err := NewSMTP(SMTPConfig{Addr: addr, From: "PR Service <pr-service@example.com>", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-from; got != "<pr-service@example.com>" {
+		t.Errorf("MAIL FROM:%s, want the bare address", got)
+	}
+
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(<-data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Get("To") != "bob@example.com" || msg.Get("From") != `"PR Service" <pr-service@example.com>` {
+		t.Errorf("headers = %v", msg)
+	}
+	if subject := msg.Get("Subject"); !strings.HasPrefix(subject, "=?utf-8?q?") {
+		t.Errorf("subject is not RFC 2047 encoded: %q", subject)
+	}
+}
+
+func TestNewSMTPRejectsInvalidFrom(t *testing.T) {
+	if _, err := NewSMTP(SMTPConfig{From: "pr-service"}); err == nil {
+		t.Fatal("expected error for a From without an address")
+	}
+}
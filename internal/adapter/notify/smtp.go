@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// SMTPConfig — почтовый сервер. Username пустой — без аутентификации;
+// STARTTLS включается, если сервер его поддерживает.
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTP — уведомления письмами
+type SMTP struct {
+	cfg SMTPConfig
+	// from — разобранный cfg.From: в MAIL FROM идёт только адрес, в заголовок — с именем
+	from *mail.Address
+}
+
+// NewSMTP проверяет адрес отправителя: From может быть как "a@b", так и "Имя <a@b>"
+func NewSMTP(cfg SMTPConfig) (*SMTP, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("smtp from %q: %w", cfg.From, err)
+	}
+	return &SMTP{cfg: cfg, from: from}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, n domain.Notification) error {
+	// в RCPT TO — только адрес, даже если получатель записан как "Имя <a@b>"
+	to, err := mail.ParseAddress(n.To)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	host, _, err := net.SplitHostPort(s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message(s.from.String(), n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message — письмо в кодировке UTF-8; тема кодируется по RFC 2047
+func message(from string, n domain.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", n.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+var _ domain.Notifier = (*SMTP)(nil)
@@ -0,0 +1,163 @@
+// Package memory — хранилище в памяти процесса для тестов и локального запуска без Postgres.
+//
+// Все репозитории одного DB разделяют общее состояние под одним мьютексом. Транзакция
+// (PRRepo.WithTx) применяет изменения сразу и записывает журнал отмены: при ошибке
+// изменения откатываются. Строки PR блокируются до конца транзакции, как SELECT ... FOR UPDATE.
+// Изоляция слабее, чем в Postgres: незафиксированные изменения видны другим читателям.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type teamRow struct {
+	settings   domain.TeamSettings
+	archivedAt *time.Time
+}
+
+type membershipKey struct {
+	team   domain.TeamName
+	userID domain.UserID
+}
+
+type prRow struct {
+	// pr — поля PR без ревьюверов
+	pr      domain.PullRequest
+	reviews []domain.Review
+	files   []string
+}
+
+type outboxRow struct {
+	event         domain.Event
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	deliveredAt   *time.Time
+}
+
+type DB struct {
+	mu sync.Mutex
+
+	teams map[domain.TeamName]teamRow
+	users map[domain.UserID]domain.User
+	// memberships — членство в командах; значение — порядковый номер добавления
+	memberships    map[membershipKey]int64
+	fallbacks      map[domain.TeamName][]domain.TeamName
+	codeOwners     map[domain.TeamName]domain.CodeOwners
+	reviewSLA      map[domain.TeamName]domain.ReviewSLA
+	unavailability map[domain.UnavailabilityID]domain.Unavailability
+	prs            map[domain.PullRequestID]prRow
+	traces         map[int64]domain.AssignmentTrace
+	outbox         map[int64]outboxRow
+	webhooks       map[domain.WebhookID]domain.WebhookSubscription
+	deliveries     map[int64]domain.WebhookDelivery
+	preferences    map[domain.UserID]domain.NotificationPreferences
+	notifications  map[int64]domain.Notification
+
+	// seq — общий счётчик идентификаторов; как и последовательности Postgres, не откатывается
+	seq int64
+
+	// rowLocks — блокировки строк PR: занятый канал означает, что строку держит транзакция
+	rowLocks map[domain.PullRequestID]chan struct{}
+}
+
+func New() *DB {
+	return &DB{
+		teams:          make(map[domain.TeamName]teamRow),
+		users:          make(map[domain.UserID]domain.User),
+		memberships:    make(map[membershipKey]int64),
+		fallbacks:      make(map[domain.TeamName][]domain.TeamName),
+		codeOwners:     make(map[domain.TeamName]domain.CodeOwners),
+		reviewSLA:      make(map[domain.TeamName]domain.ReviewSLA),
+		unavailability: make(map[domain.UnavailabilityID]domain.Unavailability),
+		prs:            make(map[domain.PullRequestID]prRow),
+		traces:         make(map[int64]domain.AssignmentTrace),
+		outbox:         make(map[int64]outboxRow),
+		webhooks:       make(map[domain.WebhookID]domain.WebhookSubscription),
+		deliveries:     make(map[int64]domain.WebhookDelivery),
+		preferences:    make(map[domain.UserID]domain.NotificationPreferences),
+		notifications:  make(map[int64]domain.Notification),
+		rowLocks:       make(map[domain.PullRequestID]chan struct{}),
+	}
+}
+
+func (db *DB) Close(_ context.Context) {}
+
+func (db *DB) nextID() int64 {
+	db.seq++
+	return db.seq
+}
+
+// lockRow блокирует строку PR; ждёт, пока её отпустит другая транзакция
+func (db *DB) lockRow(ctx context.Context, id domain.PullRequestID) (chan struct{}, error) {
+	db.mu.Lock()
+	lock, ok := db.rowLocks[id]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		db.rowLocks[id] = lock
+	}
+	db.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return lock, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// undoLog — журнал отмены транзакции; nil — изменения вне транзакции, отменять нечего
+type undoLog struct {
+	steps []func()
+}
+
+func (u *undoLog) add(step func()) {
+	if u != nil {
+		u.steps = append(u.steps, step)
+	}
+}
+
+// rollback отменяет изменения в обратном порядке; вызывается под db.mu
+func (u *undoLog) rollback() {
+	for i := len(u.steps) - 1; i >= 0; i-- {
+		u.steps[i]()
+	}
+	u.steps = nil
+}
+
+// set записывает значение по ключу, запоминая прежнее для отмены
+func set[K comparable, V any](u *undoLog, m map[K]V, k K, v V) {
+	old, existed := m[k]
+	u.add(func() {
+		if existed {
+			m[k] = old
+		} else {
+			delete(m, k)
+		}
+	})
+	m[k] = v
+}
+
+// del удаляет значение по ключу, запоминая его для отмены
+func del[K comparable, V any](u *undoLog, m map[K]V, k K) {
+	old, existed := m[k]
+	if !existed {
+		return
+	}
+	u.add(func() { m[k] = old })
+	delete(m, k)
+}
+
+var (
+	_ domain.TeamRepository         = (*TeamRepo)(nil)
+	_ domain.UserRepository         = (*UserRepo)(nil)
+	_ domain.PRRepository           = (*PRRepo)(nil)
+	_ domain.PRTx                   = (*prTx)(nil)
+	_ domain.OutboxRepository       = (*OutboxRepo)(nil)
+	_ domain.WebhookRepository      = (*WebhookRepo)(nil)
+	_ domain.NotificationRepository = (*NotificationRepo)(nil)
+)
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type NotificationRepo struct {
+	db *DB
+}
+
+func NewNotificationRepo(db *DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) GetPreferences(
+	_ context.Context,
+	userID domain.UserID,
+) (*domain.NotificationPreferences, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	p, ok := r.db.preferences[userID]
+	if !ok {
+		return nil, nil
+	}
+	p = clonePreferences(p)
+	return &p, nil
+}
+
+func (r *NotificationRepo) SetPreferences(_ context.Context, p domain.NotificationPreferences) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[p.UserID]; !ok {
+		return fmt.Errorf("memory: user %q not found", p.UserID)
+	}
+	set(nil, r.db.preferences, p.UserID, clonePreferences(p))
+	return nil
+}
+
+func (r *NotificationRepo) EnqueueNotifications(_ context.Context, notifications []domain.Notification) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, n := range notifications {
+		if r.db.hasNotification(n.EventID, n.UserID, n.Channel) {
+			continue
+		}
+		n.ID = r.db.nextID()
+		n.Status = domain.DeliveryPending
+		n.Attempts = 0
+		set(nil, r.db.notifications, n.ID, n)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) ClaimNotifications(
+	_ context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.Notification, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var ids []int64
+	for id, n := range r.db.notifications {
+		if n.Status == domain.DeliveryPending && !n.NextAttemptAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	res := make([]domain.Notification, 0, len(ids))
+	for _, id := range ids {
+		n := r.db.notifications[id]
+		n.Attempts++
+		n.NextAttemptAt = leaseUntil
+		set(nil, r.db.notifications, id, n)
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkNotificationSent(_ context.Context, id int64, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	n, ok := r.db.notifications[id]
+	if !ok {
+		return nil
+	}
+	n.Status = domain.DeliveryDelivered
+	n.LastError = ""
+	n.SentAt = &at
+	set(nil, r.db.notifications, id, n)
+	return nil
+}
+
+func (r *NotificationRepo) MarkNotificationFailed(
+	_ context.Context,
+	id int64,
+	reason string,
+	nextAttemptAt time.Time,
+	dead bool,
+) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	n, ok := r.db.notifications[id]
+	if !ok || n.Status != domain.DeliveryPending {
+		return nil
+	}
+	if dead {
+		n.Status = domain.DeliveryDead
+	}
+	n.LastError = reason
+	n.NextAttemptAt = nextAttemptAt
+	set(nil, r.db.notifications, id, n)
+	return nil
+}
+
+// ==================== общие операции (под db.mu) ====================
+
+func (db *DB) hasNotification(eventID int64, userID domain.UserID, channel domain.NotificationChannel) bool {
+	for _, n := range db.notifications {
+		if n.EventID == eventID && n.UserID == userID && n.Channel == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func clonePreferences(p domain.NotificationPreferences) domain.NotificationPreferences {
+	p.Channels = slices.Clone(p.Channels)
+	p.Events = slices.Clone(p.Events)
+	return p
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type OutboxRepo struct {
+	db *DB
+}
+
+func NewOutboxRepo(db *DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (t *prTx) SaveEvents(_ context.Context, events []domain.Event) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	for _, e := range events {
+		e.ID = t.db.nextID()
+		e.Payload = slices.Clone(e.Payload)
+		set(t.undo, t.db.outbox, e.ID, outboxRow{event: e, nextAttemptAt: e.OccurredAt})
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ClaimPending(
+	_ context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.OutboxEntry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var ids []int64
+	for id, row := range r.db.outbox {
+		if row.deliveredAt == nil && !row.nextAttemptAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	res := make([]domain.OutboxEntry, 0, len(ids))
+	for _, id := range ids {
+		row := r.db.outbox[id]
+		row.attempts++
+		row.nextAttemptAt = leaseUntil
+		set(nil, r.db.outbox, id, row)
+
+		e := row.event
+		e.Payload = slices.Clone(e.Payload)
+		res = append(res, domain.OutboxEntry{Event: e, Attempts: row.attempts})
+	}
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkDelivered(_ context.Context, id int64, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.outbox[id]
+	if !ok {
+		return nil
+	}
+	row.deliveredAt = &at
+	row.lastError = ""
+	set(nil, r.db.outbox, id, row)
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(_ context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.outbox[id]
+	if !ok || row.deliveredAt != nil {
+		return nil
+	}
+	row.lastError = reason
+	row.nextAttemptAt = nextAttemptAt
+	set(nil, r.db.outbox, id, row)
+	return nil
+}
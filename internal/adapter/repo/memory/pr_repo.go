@@ -0,0 +1,693 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type PRRepo struct {
+	db *DB
+}
+
+func NewPRRepo(db *DB) *PRRepo {
+	return &PRRepo{db: db}
+}
+
+// ==================== domain.PRRepository ====================
+
+// WithTx выполняет fn в транзакции: если fn вернула ошибку (или паниковала), все изменения
+// откатываются. Заблокированные транзакцией строки PR освобождаются после завершения.
+func (r *PRRepo) WithTx(_ context.Context, fn func(tx domain.PRTx) error) error {
+	t := &prTx{db: r.db, undo: &undoLog{}, locks: make(map[domain.PullRequestID]chan struct{})}
+
+	committed := false
+	defer func() {
+		if !committed {
+			r.db.mu.Lock()
+			t.undo.rollback()
+			r.db.mu.Unlock()
+		}
+		t.release()
+	}()
+
+	if err := fn(t); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (r *PRRepo) GetByID(_ context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.loadPR(id), nil
+}
+
+func (r *PRRepo) Create(_ context.Context, pr domain.PullRequest, assignedAt time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.createPR(nil, pr, assignedAt)
+}
+
+func (r *PRRepo) Update(_ context.Context, pr domain.PullRequest, assignedAt time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.updatePR(nil, pr, assignedAt)
+	return nil
+}
+
+func (r *PRRepo) ListByReviewer(_ context.Context, reviewerID domain.UserID) ([]domain.PullRequest, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.PullRequest
+	for id, row := range r.db.prs {
+		if slices.ContainsFunc(row.reviews, func(rv domain.Review) bool { return rv.ReviewerID == reviewerID }) {
+			res = append(res, *r.db.loadPR(id))
+		}
+	}
+	// от новых к старым (при равной дате — по убыванию id), PR без created_at — в конце
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].CreatedAt, res[j].CreatedAt
+		switch {
+		case a == nil || b == nil:
+			if a != nil || b != nil {
+				return a != nil
+			}
+		case !a.Equal(*b):
+			return a.After(*b)
+		}
+		return res[i].ID > res[j].ID
+	})
+	return res, nil
+}
+
+// List — keyset-пагинация по (created_at, pull_request_id) в порядке убывания.
+// PR без created_at считаются самыми старыми.
+func (r *PRRepo) List(_ context.Context, f domain.PRFilter) ([]domain.PullRequest, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.PullRequest
+	for id := range r.db.prs {
+		pr := r.db.loadPR(id)
+		if matchFilter(pr, f) {
+			res = append(res, *pr)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return cursorLess(cursorOf(&res[j]), cursorOf(&res[i]))
+	})
+	if f.Limit >= 0 && len(res) > f.Limit {
+		res = res[:f.Limit]
+	}
+	return res, nil
+}
+
+func (r *PRRepo) ListAssignmentTraces(_ context.Context, id domain.PullRequestID) ([]domain.AssignmentTrace, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.AssignmentTrace
+	for _, t := range r.db.traces {
+		if t.PullRequestID == id {
+			res = append(res, cloneTrace(t))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+// ListOverdueCandidates — команда PR без team_name — основная команда автора, как при назначении
+func (r *PRRepo) ListOverdueCandidates(_ context.Context, now time.Time, limit int) ([]domain.OverdueReview, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.OverdueReview
+	for id, row := range r.db.prs {
+		if row.pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		team := row.pr.TeamName
+		if team == "" {
+			team = r.db.users[row.pr.AuthorID].TeamName
+		}
+		sla, ok := r.db.reviewSLA[team]
+		if !ok || !sla.Enabled() {
+			continue
+		}
+		for _, rv := range row.reviews {
+			if rv.FirstActedAt != nil || rv.OverdueAt != nil || sla.DueAt(rv.AssignedAt).After(now) {
+				continue
+			}
+			res = append(res, domain.OverdueReview{
+				PullRequestID:   id,
+				PullRequestName: row.pr.Name,
+				AuthorID:        row.pr.AuthorID,
+				TeamName:        team,
+				ReviewerID:      rv.ReviewerID,
+				AssignedAt:      rv.AssignedAt,
+				SLA:             sla,
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if !a.AssignedAt.Equal(b.AssignedAt) {
+			return a.AssignedAt.Before(b.AssignedAt)
+		}
+		if a.PullRequestID != b.PullRequestID {
+			return a.PullRequestID < b.PullRequestID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// ==================== domain.PRTx ====================
+
+type prTx struct {
+	db   *DB
+	undo *undoLog
+	// locks — строки PR, заблокированные транзакцией
+	locks map[domain.PullRequestID]chan struct{}
+}
+
+// lock блокирует строку PR до конца транзакции (повторная блокировка своей строки ничего не делает)
+func (t *prTx) lock(ctx context.Context, id domain.PullRequestID) error {
+	if _, ok := t.locks[id]; ok {
+		return nil
+	}
+	lock, err := t.db.lockRow(ctx, id)
+	if err != nil {
+		return err
+	}
+	t.locks[id] = lock
+	return nil
+}
+
+func (t *prTx) release() {
+	for id, lock := range t.locks {
+		<-lock
+		delete(t.locks, id)
+	}
+}
+
+func (t *prTx) GetByIDForUpdate(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	if err := t.lock(ctx, id); err != nil {
+		return nil, err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.loadPR(id), nil
+}
+
+func (t *prTx) Create(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
+	if err := t.lock(ctx, pr.ID); err != nil {
+		return err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.createPR(t.undo, pr, assignedAt)
+}
+
+func (t *prTx) ListChangedFiles(_ context.Context, id domain.PullRequestID) ([]string, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	files := slices.Clone(t.db.prs[id].files)
+	slices.Sort(files)
+	return files, nil
+}
+
+func (t *prTx) Update(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
+	if err := t.lock(ctx, pr.ID); err != nil {
+		return err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	t.db.updatePR(t.undo, pr, assignedAt)
+	return nil
+}
+
+func (t *prTx) ListOpenByReviewersForUpdate(
+	ctx context.Context,
+	reviewerIDs []domain.UserID,
+) ([]domain.PullRequest, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+
+	t.db.mu.Lock()
+	ids := t.db.openByReviewers(reviewerIDs)
+	t.db.mu.Unlock()
+
+	// блокируем в порядке id, как ORDER BY ... FOR UPDATE
+	for _, id := range ids {
+		if err := t.lock(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	// после блокировки условие проверяется заново — PR мог измениться
+	var res []domain.PullRequest
+	for _, id := range t.db.openByReviewers(reviewerIDs) {
+		if _, ok := t.locks[id]; ok {
+			res = append(res, *t.db.loadPR(id))
+		}
+	}
+	return res, nil
+}
+
+func (t *prTx) ReplaceReviewers(
+	ctx context.Context,
+	changes []domain.ReviewReassignment,
+	assignedAt time.Time,
+) error {
+	for _, c := range changes {
+		if err := t.lock(ctx, c.PullRequestID); err != nil {
+			return err
+		}
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	for _, c := range changes {
+		row, ok := t.db.prs[c.PullRequestID]
+		if !ok {
+			continue
+		}
+		reviews := make([]domain.Review, 0, len(row.reviews))
+		for _, rv := range row.reviews {
+			if rv.ReviewerID != c.OldReviewerID {
+				reviews = append(reviews, rv)
+				continue
+			}
+			if c.NewReviewerID != "" {
+				reviews = append(reviews, domain.Review{
+					ReviewerID: c.NewReviewerID,
+					State:      domain.ReviewPending,
+					AssignedAt: assignedAt,
+				})
+			}
+		}
+		row.reviews = reviews
+		set(t.undo, t.db.prs, c.PullRequestID, row)
+	}
+	return nil
+}
+
+func (t *prTx) SetReviewState(
+	ctx context.Context,
+	id domain.PullRequestID,
+	reviewerID domain.UserID,
+	state domain.ReviewState,
+	at time.Time,
+) error {
+	if err := t.lock(ctx, id); err != nil {
+		return err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	row, ok := t.db.prs[id]
+	if !ok {
+		return nil
+	}
+	row.reviews = slices.Clone(row.reviews)
+	for i := range row.reviews {
+		if row.reviews[i].ReviewerID == reviewerID {
+			row.reviews[i].State = state
+			row.reviews[i].SubmittedAt = &at
+			if row.reviews[i].FirstActedAt == nil {
+				row.reviews[i].FirstActedAt = &at
+			}
+		}
+	}
+	set(t.undo, t.db.prs, id, row)
+	return nil
+}
+
+func (t *prTx) MarkReviewOverdue(
+	ctx context.Context,
+	id domain.PullRequestID,
+	reviewerID domain.UserID,
+	assignedAt, at time.Time,
+) (bool, error) {
+	if err := t.lock(ctx, id); err != nil {
+		return false, err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	row, ok := t.db.prs[id]
+	if !ok {
+		return false, nil
+	}
+	i := slices.IndexFunc(row.reviews, func(rv domain.Review) bool { return rv.ReviewerID == reviewerID })
+	if i == -1 {
+		return false, nil
+	}
+	rv := row.reviews[i]
+	if !rv.AssignedAt.Equal(assignedAt) || rv.FirstActedAt != nil || rv.OverdueAt != nil {
+		return false, nil
+	}
+	row.reviews = slices.Clone(row.reviews)
+	row.reviews[i].OverdueAt = &at
+	set(t.undo, t.db.prs, id, row)
+	return true, nil
+}
+
+func (t *prTx) SetUserIsActive(_ context.Context, userID domain.UserID, isActive bool) (*domain.User, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.setIsActive(t.undo, userID, isActive), nil
+}
+
+func (t *prTx) DeactivateUsers(_ context.Context, userIDs []domain.UserID) ([]domain.User, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	var res []domain.User
+	seen := make(map[domain.UserID]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		if u := t.db.setIsActive(t.undo, id, false); u != nil {
+			res = append(res, *u)
+		}
+	}
+	return res, nil
+}
+
+func (t *prTx) CreateTeam(_ context.Context, team domain.Team) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.createTeam(t.undo, team)
+}
+
+func (t *prTx) UpsertUser(_ context.Context, u domain.User) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.upsertUser(t.undo, u)
+}
+
+func (t *prTx) SetUserTeam(_ context.Context, userID domain.UserID, team domain.TeamName) (*domain.User, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	u, ok := t.db.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := t.db.teams[team]; team != "" && !ok {
+		return nil, fmt.Errorf("memory: team %q not found", team)
+	}
+	u.TeamName = team
+	set(t.undo, t.db.users, userID, u)
+	return cloneUser(u), nil
+}
+
+func (t *prTx) AddTeamMember(_ context.Context, team domain.TeamName, userID domain.UserID) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return t.db.addMember(t.undo, team, userID)
+}
+
+func (t *prTx) RemoveTeamMember(_ context.Context, team domain.TeamName, userID domain.UserID) (*domain.User, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	key := membershipKey{team: team, userID: userID}
+	if _, ok := t.db.memberships[key]; !ok {
+		return nil, nil
+	}
+	del(t.undo, t.db.memberships, key)
+
+	u := t.db.users[userID]
+	if u.TeamName == team {
+		u.TeamName = ""
+		if rest := t.db.userTeams(userID); len(rest) > 0 {
+			u.TeamName = rest[0]
+		}
+		set(t.undo, t.db.users, userID, u)
+	}
+	return cloneUser(u), nil
+}
+
+func (t *prTx) CountOpenPRsByTeam(_ context.Context, team domain.TeamName) (int, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	isMember := func(id domain.UserID) bool {
+		_, ok := t.db.memberships[membershipKey{team: team, userID: id}]
+		return ok
+	}
+
+	n := 0
+	for _, row := range t.db.prs {
+		if row.pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		if row.pr.TeamName == team || isMember(row.pr.AuthorID) ||
+			slices.ContainsFunc(row.reviews, func(rv domain.Review) bool { return isMember(rv.ReviewerID) }) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (t *prTx) DeleteTeam(_ context.Context, team domain.TeamName) (bool, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	if _, ok := t.db.teams[team]; !ok {
+		return false, nil
+	}
+
+	// основной командой участников становится другая их команда (если есть)
+	for id, u := range t.db.users {
+		if u.TeamName != team {
+			continue
+		}
+		u.TeamName = ""
+		for _, other := range t.db.userTeams(id) {
+			if other != team {
+				u.TeamName = other
+				break
+			}
+		}
+		set(t.undo, t.db.users, id, u)
+	}
+
+	t.db.deleteTeam(t.undo, team)
+	return true, nil
+}
+
+func (t *prTx) SaveAssignmentTrace(ctx context.Context, trace domain.AssignmentTrace) error {
+	return t.SaveAssignmentTraces(ctx, []domain.AssignmentTrace{trace})
+}
+
+func (t *prTx) SaveAssignmentTraces(_ context.Context, traces []domain.AssignmentTrace) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	for _, trace := range traces {
+		if _, ok := t.db.prs[trace.PullRequestID]; !ok {
+			return fmt.Errorf("memory: pull request %q not found", trace.PullRequestID)
+		}
+		trace = cloneTrace(trace)
+		trace.ID = t.db.nextID()
+		set(t.undo, t.db.traces, trace.ID, trace)
+	}
+	return nil
+}
+
+// ==================== общие операции (под db.mu) ====================
+
+// loadPR — копия PR вместе с ревьюверами; nil — PR нет
+func (db *DB) loadPR(id domain.PullRequestID) *domain.PullRequest {
+	row, ok := db.prs[id]
+	if !ok {
+		return nil
+	}
+
+	pr := row.pr
+	pr.CreatedAt = cloneTime(pr.CreatedAt)
+	pr.MergedAt = cloneTime(pr.MergedAt)
+	pr.ClosedAt = cloneTime(pr.ClosedAt)
+	pr.Reviews = make([]domain.Review, len(row.reviews))
+	pr.AssignedReviewers = make([]domain.UserID, len(row.reviews))
+	for i, rv := range row.reviews {
+		rv.SubmittedAt = cloneTime(rv.SubmittedAt)
+		rv.FirstActedAt = cloneTime(rv.FirstActedAt)
+		rv.OverdueAt = cloneTime(rv.OverdueAt)
+		pr.Reviews[i] = rv
+		pr.AssignedReviewers[i] = rv.ReviewerID
+	}
+	return &pr
+}
+
+func (db *DB) createPR(u *undoLog, pr domain.PullRequest, assignedAt time.Time) error {
+	if _, ok := db.prs[pr.ID]; ok {
+		return domain.ErrPRExists
+	}
+
+	files := make([]string, 0, len(pr.ChangedFiles))
+	for _, f := range pr.ChangedFiles {
+		if !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	row := prRow{pr: prFields(pr), files: files}
+	row.reviews = syncReviews(nil, pr.AssignedReviewers, assignedAt)
+	set(u, db.prs, pr.ID, row)
+	return nil
+}
+
+func (db *DB) updatePR(u *undoLog, pr domain.PullRequest, assignedAt time.Time) {
+	row, ok := db.prs[pr.ID]
+	if !ok {
+		return
+	}
+	row.pr = prFields(pr)
+	row.reviews = syncReviews(row.reviews, pr.AssignedReviewers, assignedAt)
+	set(u, db.prs, pr.ID, row)
+}
+
+// openByReviewers — id OPEN PR, где ревьювер кто-то из reviewerIDs, в порядке id
+func (db *DB) openByReviewers(reviewerIDs []domain.UserID) []domain.PullRequestID {
+	var res []domain.PullRequestID
+	for id, row := range db.prs {
+		if row.pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		if slices.ContainsFunc(row.reviews, func(rv domain.Review) bool {
+			return slices.Contains(reviewerIDs, rv.ReviewerID)
+		}) {
+			res = append(res, id)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+// prFields — поля, которые хранятся в строке pull_requests
+func prFields(pr domain.PullRequest) domain.PullRequest {
+	return domain.PullRequest{
+		ID:        pr.ID,
+		Name:      pr.Name,
+		AuthorID:  pr.AuthorID,
+		Status:    pr.Status,
+		CreatedAt: cloneTime(pr.CreatedAt),
+		MergedAt:  cloneTime(pr.MergedAt),
+		ClosedAt:  cloneTime(pr.ClosedAt),
+		TeamName:  pr.TeamName,
+	}
+}
+
+// syncReviews синхронизирует список ревьюверов: лишние удаляются, новые добавляются
+// в состоянии PENDING с временем назначения assignedAt, состояние оставшихся сохраняется
+func syncReviews(current []domain.Review, reviewers []domain.UserID, assignedAt time.Time) []domain.Review {
+	res := make([]domain.Review, 0, len(reviewers))
+	for _, rv := range current {
+		if slices.Contains(reviewers, rv.ReviewerID) {
+			res = append(res, rv)
+		}
+	}
+	for _, id := range reviewers {
+		if !slices.ContainsFunc(res, func(rv domain.Review) bool { return rv.ReviewerID == id }) {
+			res = append(res, domain.Review{ReviewerID: id, State: domain.ReviewPending, AssignedAt: assignedAt})
+		}
+	}
+	return res
+}
+
+func matchFilter(pr *domain.PullRequest, f domain.PRFilter) bool {
+	switch {
+	case f.Status != "" && pr.Status != f.Status:
+		return false
+	case f.AuthorID != "" && pr.AuthorID != f.AuthorID:
+		return false
+	case f.TeamName != "" && pr.TeamName != f.TeamName:
+		return false
+	case f.ReviewerID != "" && !slices.Contains(pr.AssignedReviewers, f.ReviewerID):
+		return false
+	case f.CreatedFrom != nil && (pr.CreatedAt == nil || pr.CreatedAt.Before(*f.CreatedFrom)):
+		return false
+	case f.CreatedTo != nil && (pr.CreatedAt == nil || !pr.CreatedAt.Before(*f.CreatedTo)):
+		return false
+	case f.MergedFrom != nil && (pr.MergedAt == nil || pr.MergedAt.Before(*f.MergedFrom)):
+		return false
+	case f.MergedTo != nil && (pr.MergedAt == nil || !pr.MergedAt.Before(*f.MergedTo)):
+		return false
+	case f.After != nil && !cursorLess(cursorOf(pr), *f.After):
+		return false
+	}
+	return true
+}
+
+// cursorOf — позиция PR в выдаче; PR без created_at — самые старые
+func cursorOf(pr *domain.PullRequest) domain.PRCursor {
+	c := domain.PRCursor{ID: pr.ID}
+	if pr.CreatedAt != nil {
+		c.CreatedAt = *pr.CreatedAt
+	}
+	return c
+}
+
+func cursorLess(a, b domain.PRCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func cloneTrace(t domain.AssignmentTrace) domain.AssignmentTrace {
+	teams := make([]domain.TeamTrace, len(t.Teams))
+	for i, tt := range t.Teams {
+		tt.Candidates = slices.Clone(tt.Candidates)
+		tt.Excluded = slices.Clone(tt.Excluded)
+		teams[i] = tt
+	}
+	t.Teams = teams
+
+	selected := make([]domain.ReviewerAssignment, len(t.Selected))
+	for i, a := range t.Selected {
+		a.Paths = slices.Clone(a.Paths)
+		selected[i] = a
+	}
+	t.Selected = selected
+	return t
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+func seed(t *testing.T) (*DB, *PRRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	db := New()
+	teams, users, prs := NewTeamRepo(db), NewUserRepo(db), NewPRRepo(db)
+
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []domain.UserID{"u1", "u2", "u3"} {
+		if err := users.UpsertUser(ctx, domain.User{ID: id, Username: string(id), TeamName: "backend", IsActive: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.AddMember(ctx, "backend", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := prs.Create(ctx, domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		Status:            domain.PRStatusOpen,
+		TeamName:          "backend",
+		AssignedReviewers: []domain.UserID{"u2"},
+	}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	return db, prs
+}
+
+func TestWithTxRollback(t *testing.T) {
+	ctx := context.Background()
+	db, prs := seed(t)
+	errBoom := errors.New("boom")
+
+	err := prs.WithTx(ctx, func(tx domain.PRTx) error {
+		pr, err := tx.GetByIDForUpdate(ctx, "pr-1")
+		if err != nil {
+			return err
+		}
+		pr.AssignedReviewers = []domain.UserID{"u3"}
+		if err := tx.Update(ctx, *pr, time.Now()); err != nil {
+			return err
+		}
+		if _, err := tx.DeactivateUsers(ctx, []domain.UserID{"u2"}); err != nil {
+			return err
+		}
+		if _, err := tx.RemoveTeamMember(ctx, "backend", "u2"); err != nil {
+			return err
+		}
+		if err := tx.Create(ctx, domain.PullRequest{ID: "pr-2", AuthorID: "u1", Status: domain.PRStatusOpen}, time.Now()); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got %v, want %v", err, errBoom)
+	}
+
+	if err := prs.Create(ctx, domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusOpen}, time.Now()); !errors.Is(err, domain.ErrPRExists) {
+		t.Fatalf("duplicate create: got %v, want %v", err, domain.ErrPRExists)
+	}
+
+	pr, _ := prs.GetByID(ctx, "pr-1")
+	if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u2" {
+		t.Fatalf("reviewers not rolled back: %v", pr.AssignedReviewers)
+	}
+	if pr, _ := prs.GetByID(ctx, "pr-2"); pr != nil {
+		t.Fatal("created PR not rolled back")
+	}
+	u, _ := NewUserRepo(db).GetByID(ctx, "u2")
+	if !u.IsActive || u.TeamName != "backend" {
+		t.Fatalf("user not rolled back: %+v", u)
+	}
+	team, _ := NewTeamRepo(db).GetTeam(ctx, "backend")
+	if len(team.Members) != 3 {
+		t.Fatalf("membership not rolled back: %+v", team.Members)
+	}
+}
+
+func TestGetByIDForUpdateLocksRow(t *testing.T) {
+	ctx := context.Background()
+	_, prs := seed(t)
+
+	locked := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = prs.WithTx(ctx, func(tx domain.PRTx) error {
+			if _, err := tx.GetByIDForUpdate(ctx, "pr-1"); err != nil {
+				return err
+			}
+			close(locked)
+			time.Sleep(50 * time.Millisecond)
+			return tx.SetReviewState(ctx, "pr-1", "u2", domain.ReviewApproved, time.Now())
+		})
+	}()
+	<-locked
+
+	// вторая транзакция ждёт первую и видит её изменения
+	err := prs.WithTx(ctx, func(tx domain.PRTx) error {
+		pr, err := tx.GetByIDForUpdate(ctx, "pr-1")
+		if err != nil {
+			return err
+		}
+		if pr.Approvals() != 1 {
+			t.Errorf("second transaction did not wait for the first one: %+v", pr.Reviews)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	// ожидание блокировки прерывается отменой контекста
+	hold := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = prs.WithTx(ctx, func(tx domain.PRTx) error {
+			_, err := tx.GetByIDForUpdate(ctx, "pr-1")
+			close(hold)
+			<-release
+			return err
+		})
+	}()
+	<-hold
+	defer close(release)
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = prs.WithTx(timeout, func(tx domain.PRTx) error {
+		_, err := tx.GetByIDForUpdate(timeout, "pr-1")
+		return err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,302 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type TeamRepo struct {
+	db *DB
+}
+
+func NewTeamRepo(db *DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+func (r *TeamRepo) CreateTeam(_ context.Context, team domain.Team) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.createTeam(nil, team)
+}
+
+func (r *TeamRepo) GetTeam(_ context.Context, name domain.TeamName) (*domain.Team, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.teams[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var members []domain.TeamMember
+	for _, id := range r.db.memberIDs(name) {
+		u := r.db.users[id]
+		members = append(members, domain.TeamMember{UserID: u.ID, Username: u.Username, IsActive: u.IsActive})
+	}
+
+	return &domain.Team{
+		Name:       name,
+		Members:    members,
+		Settings:   row.settings,
+		ArchivedAt: cloneTime(row.archivedAt),
+		Fallbacks:  r.db.activeFallbacks(name),
+	}, nil
+}
+
+func (r *TeamRepo) GetSettings(_ context.Context, name domain.TeamName) (*domain.TeamSettings, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.teams[name]
+	if !ok {
+		return nil, nil
+	}
+	settings := row.settings
+	return &settings, nil
+}
+
+func (r *TeamRepo) UpdateSettings(
+	_ context.Context,
+	name domain.TeamName,
+	settings domain.TeamSettings,
+) (*domain.TeamSettings, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.teams[name]
+	if !ok || row.archivedAt != nil {
+		return nil, nil
+	}
+	row.settings = settings
+	set(nil, r.db.teams, name, row)
+	return &settings, nil
+}
+
+func (r *TeamRepo) GetFallbacks(_ context.Context, name domain.TeamName) ([]domain.TeamName, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.activeFallbacks(name), nil
+}
+
+func (r *TeamRepo) SetFallbacks(_ context.Context, name domain.TeamName, fallbacks []domain.TeamName) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, f := range fallbacks {
+		if _, ok := r.db.teams[f]; !ok || f == name {
+			return fmt.Errorf("memory: invalid fallback team %q", f)
+		}
+	}
+	if len(fallbacks) == 0 {
+		del(nil, r.db.fallbacks, name)
+		return nil
+	}
+	set(nil, r.db.fallbacks, name, slices.Clone(fallbacks))
+	return nil
+}
+
+func (r *TeamRepo) GetCodeOwners(_ context.Context, name domain.TeamName) (*domain.CodeOwners, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	res := cloneCodeOwners(r.db.codeOwners[name])
+	return &res, nil
+}
+
+func (r *TeamRepo) SetCodeOwners(_ context.Context, name domain.TeamName, owners domain.CodeOwners) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	owners = cloneCodeOwners(owners)
+	// как в team_owner_groups: группы по имени, участники по user_id без повторов
+	sort.Slice(owners.Groups, func(i, j int) bool {
+		return owners.Groups[i].Name < owners.Groups[j].Name
+	})
+	for i := range owners.Groups {
+		slices.Sort(owners.Groups[i].Members)
+		owners.Groups[i].Members = slices.Compact(owners.Groups[i].Members)
+	}
+	set(nil, r.db.codeOwners, name, owners)
+	return nil
+}
+
+func (r *TeamRepo) GetReviewSLA(_ context.Context, name domain.TeamName) (*domain.ReviewSLA, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	sla, ok := r.db.reviewSLA[name]
+	if !ok {
+		sla = domain.ReviewSLA{Policy: domain.DefaultSLAPolicy}
+	}
+	return &sla, nil
+}
+
+func (r *TeamRepo) SetReviewSLA(_ context.Context, name domain.TeamName, sla domain.ReviewSLA) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.teams[name]; !ok {
+		return fmt.Errorf("memory: team %q not found", name)
+	}
+	set(nil, r.db.reviewSLA, name, sla)
+	return nil
+}
+
+func (r *TeamRepo) AddMember(_ context.Context, name domain.TeamName, userID domain.UserID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.addMember(nil, name, userID)
+}
+
+func (r *TeamRepo) ArchiveTeam(_ context.Context, name domain.TeamName, at time.Time) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.teams[name]
+	if !ok {
+		return false, nil
+	}
+	if row.archivedAt == nil {
+		row.archivedAt = &at
+		set(nil, r.db.teams, name, row)
+	}
+	return true, nil
+}
+
+// ==================== общие операции (под db.mu) ====================
+
+// memberIDs — участники команды в порядке user_id
+func (db *DB) memberIDs(team domain.TeamName) []domain.UserID {
+	var res []domain.UserID
+	for k := range db.memberships {
+		if k.team == team {
+			res = append(res, k.userID)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+// userTeams — команды пользователя в порядке добавления
+func (db *DB) userTeams(userID domain.UserID) []domain.TeamName {
+	type membership struct {
+		team domain.TeamName
+		seq  int64
+	}
+	var ms []membership
+	for k, seq := range db.memberships {
+		if k.userID == userID {
+			ms = append(ms, membership{team: k.team, seq: seq})
+		}
+	}
+	sort.Slice(ms, func(i, j int) bool {
+		if ms[i].seq != ms[j].seq {
+			return ms[i].seq < ms[j].seq
+		}
+		return ms[i].team < ms[j].team
+	})
+
+	res := make([]domain.TeamName, len(ms))
+	for i, m := range ms {
+		res[i] = m.team
+	}
+	return res
+}
+
+// activeFallbacks — запасные команды без архивных
+func (db *DB) activeFallbacks(team domain.TeamName) []domain.TeamName {
+	var res []domain.TeamName
+	for _, f := range db.fallbacks[team] {
+		if row, ok := db.teams[f]; ok && row.archivedAt == nil {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func (db *DB) createTeam(u *undoLog, team domain.Team) error {
+	if _, ok := db.teams[team.Name]; ok {
+		return fmt.Errorf("memory: team %q already exists", team.Name)
+	}
+	set(u, db.teams, team.Name, teamRow{settings: team.Settings})
+	return nil
+}
+
+func (db *DB) addMember(u *undoLog, team domain.TeamName, userID domain.UserID) error {
+	if _, ok := db.teams[team]; !ok {
+		return fmt.Errorf("memory: team %q not found", team)
+	}
+	if _, ok := db.users[userID]; !ok {
+		return fmt.Errorf("memory: user %q not found", userID)
+	}
+	key := membershipKey{team: team, userID: userID}
+	if _, ok := db.memberships[key]; !ok {
+		set(u, db.memberships, key, db.nextID())
+	}
+	return nil
+}
+
+// deleteTeam удаляет команду и всё, что ссылается на неё (как ON DELETE CASCADE / SET NULL)
+func (db *DB) deleteTeam(u *undoLog, team domain.TeamName) {
+	for k := range db.memberships {
+		if k.team == team {
+			del(u, db.memberships, k)
+		}
+	}
+	for name, fallbacks := range db.fallbacks {
+		if name == team {
+			del(u, db.fallbacks, name)
+			continue
+		}
+		if i := slices.Index(fallbacks, team); i >= 0 {
+			set(u, db.fallbacks, name, slices.Delete(slices.Clone(fallbacks), i, i+1))
+		}
+	}
+	del(u, db.codeOwners, team)
+	del(u, db.reviewSLA, team)
+	for id, sub := range db.webhooks {
+		if sub.Team == team {
+			db.deleteWebhook(u, id)
+		}
+	}
+	for id, usr := range db.users {
+		if usr.TeamName == team {
+			usr.TeamName = ""
+			set(u, db.users, id, usr)
+		}
+	}
+	for id, row := range db.prs {
+		if row.pr.TeamName == team {
+			row.pr.TeamName = ""
+			set(u, db.prs, id, row)
+		}
+	}
+	del(u, db.teams, team)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
+
+func cloneCodeOwners(c domain.CodeOwners) domain.CodeOwners {
+	res := domain.CodeOwners{}
+	for _, r := range c.Rules {
+		res.Rules = append(res.Rules, domain.CodeOwnerRule{Pattern: r.Pattern, Owners: slices.Clone(r.Owners)})
+	}
+	for _, g := range c.Groups {
+		res.Groups = append(res.Groups, domain.CodeOwnerGroup{Name: g.Name, Members: slices.Clone(g.Members)})
+	}
+	return res
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type UserRepo struct {
+	db *DB
+}
+
+func NewUserRepo(db *DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) UpsertUser(_ context.Context, u domain.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.upsertUser(nil, u)
+}
+
+func (r *UserRepo) SetIsActive(_ context.Context, userID domain.UserID, isActive bool) (*domain.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.setIsActive(nil, userID, isActive), nil
+}
+
+func (r *UserRepo) GetByID(_ context.Context, userID domain.UserID) (*domain.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u, ok := r.db.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneUser(u), nil
+}
+
+func (r *UserRepo) SetMaxOpenReviews(
+	_ context.Context,
+	userID domain.UserID,
+	maxOpenReviews *int,
+) (*domain.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u, ok := r.db.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	if maxOpenReviews != nil {
+		v := *maxOpenReviews
+		maxOpenReviews = &v
+	}
+	u.MaxOpenReviews = maxOpenReviews
+	set(nil, r.db.users, userID, u)
+	return cloneUser(u), nil
+}
+
+// ListCandidatesWithLoad — участники неархивной команды, количество OPEN PR, где они ревьюверы,
+// и признак недоступности в момент at
+func (r *UserRepo) ListCandidatesWithLoad(
+	_ context.Context,
+	team domain.TeamName,
+	at time.Time,
+) ([]domain.Candidate, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.candidates(team, at), nil
+}
+
+// ==================== недоступность ====================
+
+func (r *UserRepo) AddUnavailability(_ context.Context, u domain.Unavailability) (*domain.Unavailability, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[u.UserID]; !ok {
+		return nil, fmt.Errorf("memory: user %q not found", u.UserID)
+	}
+	u.ID = domain.UnavailabilityID(r.db.nextID())
+	set(nil, r.db.unavailability, u.ID, u)
+	return &u, nil
+}
+
+func (r *UserRepo) ListUnavailability(_ context.Context, userID domain.UserID) ([]domain.Unavailability, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.Unavailability
+	for _, u := range r.db.unavailability {
+		if u.UserID == userID {
+			res = append(res, u)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].StartsAt.Equal(res[j].StartsAt) {
+			return res[i].StartsAt.Before(res[j].StartsAt)
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *UserRepo) DeleteUnavailability(_ context.Context, id domain.UnavailabilityID) (*domain.Unavailability, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u, ok := r.db.unavailability[id]
+	if !ok {
+		return nil, nil
+	}
+	del(nil, r.db.unavailability, id)
+	return &u, nil
+}
+
+// ==================== общие операции (под db.mu) ====================
+
+// candidates — участники неархивной команды с нагрузкой и недоступностью на момент now, в порядке user_id
+func (db *DB) candidates(team domain.TeamName, now time.Time) []domain.Candidate {
+	row, ok := db.teams[team]
+	if !ok || row.archivedAt != nil {
+		return nil
+	}
+
+	var res []domain.Candidate
+	for _, id := range db.memberIDs(team) {
+		res = append(res, domain.Candidate{
+			User:        *cloneUser(db.users[id]),
+			OpenReviews: db.openReviews(id),
+			Unavailable: db.unavailableAt(id, now),
+		})
+	}
+	return res
+}
+
+// openReviews — число OPEN PR, где пользователь ревьювер
+func (db *DB) openReviews(userID domain.UserID) int {
+	n := 0
+	for _, row := range db.prs {
+		if row.pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		for _, rv := range row.reviews {
+			if rv.ReviewerID == userID {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+func (db *DB) unavailableAt(userID domain.UserID, now time.Time) bool {
+	for _, u := range db.unavailability {
+		if u.UserID == userID && !now.Before(u.StartsAt) && now.Before(u.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (db *DB) upsertUser(u *undoLog, usr domain.User) error {
+	if usr.TeamName != "" {
+		if _, ok := db.teams[usr.TeamName]; !ok {
+			return fmt.Errorf("memory: team %q not found", usr.TeamName)
+		}
+	}
+
+	if existing, ok := db.users[usr.ID]; ok {
+		existing.Username = usr.Username
+		existing.IsActive = usr.IsActive
+		if existing.TeamName == "" {
+			existing.TeamName = usr.TeamName
+		}
+		set(u, db.users, usr.ID, existing)
+		return nil
+	}
+
+	set(u, db.users, usr.ID, domain.User{
+		ID:       usr.ID,
+		Username: usr.Username,
+		TeamName: usr.TeamName,
+		IsActive: usr.IsActive,
+	})
+	return nil
+}
+
+func (db *DB) setIsActive(u *undoLog, userID domain.UserID, isActive bool) *domain.User {
+	usr, ok := db.users[userID]
+	if !ok {
+		return nil
+	}
+	usr.IsActive = isActive
+	set(u, db.users, userID, usr)
+	return cloneUser(usr)
+}
+
+func cloneUser(u domain.User) *domain.User {
+	if u.MaxOpenReviews != nil {
+		v := *u.MaxOpenReviews
+		u.MaxOpenReviews = &v
+	}
+	return &u
+}
@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type WebhookRepo struct {
+	db *DB
+}
+
+func NewWebhookRepo(db *DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+func (r *WebhookRepo) CreateSubscription(
+	_ context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	sub = cloneSubscription(sub)
+	sub.ID = domain.WebhookID(r.db.nextID())
+	set(nil, r.db.webhooks, sub.ID, sub)
+	res := cloneSubscription(sub)
+	return &res, nil
+}
+
+func (r *WebhookRepo) GetSubscription(_ context.Context, id domain.WebhookID) (*domain.WebhookSubscription, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	sub, ok := r.db.webhooks[id]
+	if !ok {
+		return nil, nil
+	}
+	res := cloneSubscription(sub)
+	return &res, nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(_ context.Context, team domain.TeamName) ([]domain.WebhookSubscription, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.WebhookSubscription
+	for _, sub := range r.db.webhooks {
+		if team == "" || sub.Team == team {
+			res = append(res, cloneSubscription(sub))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (r *WebhookRepo) UpdateSubscription(
+	_ context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	existing, ok := r.db.webhooks[sub.ID]
+	if !ok {
+		return nil, nil
+	}
+	existing.URL = sub.URL
+	existing.Secret = sub.Secret
+	existing.Events = slices.Clone(sub.Events)
+	set(nil, r.db.webhooks, sub.ID, existing)
+	res := cloneSubscription(existing)
+	return &res, nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(_ context.Context, id domain.WebhookID) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.webhooks[id]; !ok {
+		return false, nil
+	}
+	r.db.deleteWebhook(nil, id)
+	return true, nil
+}
+
+func (r *WebhookRepo) EnqueueDeliveries(
+	_ context.Context,
+	e domain.Event,
+	subs []domain.WebhookID,
+	at time.Time,
+) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, subID := range subs {
+		if _, ok := r.db.webhooks[subID]; !ok {
+			continue
+		}
+		if r.db.hasDelivery(subID, e.ID) {
+			continue
+		}
+		d := domain.WebhookDelivery{
+			ID:             r.db.nextID(),
+			SubscriptionID: subID,
+			Event:          e,
+			Status:         domain.DeliveryPending,
+			NextAttemptAt:  at,
+			CreatedAt:      at,
+		}
+		d.Event.Payload = slices.Clone(e.Payload)
+		set(nil, r.db.deliveries, d.ID, d)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) ClaimDeliveries(
+	_ context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var ids []int64
+	for id, d := range r.db.deliveries {
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	res := make([]domain.WebhookDelivery, 0, len(ids))
+	for _, id := range ids {
+		d := r.db.deliveries[id]
+		d.Attempts++
+		d.NextAttemptAt = leaseUntil
+		set(nil, r.db.deliveries, id, d)
+		res = append(res, cloneDelivery(d))
+	}
+	return res, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(_ context.Context, id int64, attempt domain.WebhookAttempt) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	d, ok := r.db.deliveries[id]
+	if !ok {
+		return nil
+	}
+	d.Status = domain.DeliveryDelivered
+	d.ResponseStatus = attempt.ResponseStatus
+	d.LastError = ""
+	d.DeliveredAt = &attempt.At
+	set(nil, r.db.deliveries, id, d)
+	return nil
+}
+
+func (r *WebhookRepo) MarkFailed(
+	_ context.Context,
+	id int64,
+	attempt domain.WebhookAttempt,
+	nextAttemptAt time.Time,
+	dead bool,
+) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	d, ok := r.db.deliveries[id]
+	if !ok || d.Status != domain.DeliveryPending {
+		return nil
+	}
+	if dead {
+		d.Status = domain.DeliveryDead
+	}
+	d.ResponseStatus = attempt.ResponseStatus
+	d.LastError = attempt.Error
+	d.NextAttemptAt = nextAttemptAt
+	set(nil, r.db.deliveries, id, d)
+	return nil
+}
+
+func (r *WebhookRepo) ListDeliveries(
+	_ context.Context,
+	f domain.WebhookDeliveryFilter,
+) ([]domain.WebhookDelivery, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var res []domain.WebhookDelivery
+	for _, d := range r.db.deliveries {
+		if f.SubscriptionID != 0 && d.SubscriptionID != f.SubscriptionID {
+			continue
+		}
+		if f.Status != "" && d.Status != f.Status {
+			continue
+		}
+		res = append(res, cloneDelivery(d))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID > res[j].ID })
+	if len(res) > f.Limit {
+		res = res[:f.Limit]
+	}
+	return res, nil
+}
+
+func (r *WebhookRepo) RequeueDelivery(_ context.Context, id int64, at time.Time) (*domain.WebhookDelivery, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	d, ok := r.db.deliveries[id]
+	if !ok || d.Status != domain.DeliveryDead {
+		return nil, nil
+	}
+	d.Status = domain.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	set(nil, r.db.deliveries, id, d)
+	res := cloneDelivery(d)
+	return &res, nil
+}
+
+// ==================== общие операции (под db.mu) ====================
+
+func (db *DB) hasDelivery(subID domain.WebhookID, eventID int64) bool {
+	for _, d := range db.deliveries {
+		if d.SubscriptionID == subID && d.Event.ID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteWebhook удаляет подписку вместе с журналом доставок
+func (db *DB) deleteWebhook(u *undoLog, id domain.WebhookID) {
+	for did, d := range db.deliveries {
+		if d.SubscriptionID == id {
+			del(u, db.deliveries, did)
+		}
+	}
+	del(u, db.webhooks, id)
+}
+
+func cloneSubscription(s domain.WebhookSubscription) domain.WebhookSubscription {
+	s.Events = slices.Clone(s.Events)
+	return s
+}
+
+func cloneDelivery(d domain.WebhookDelivery) domain.WebhookDelivery {
+	d.Event.Payload = slices.Clone(d.Event.Payload)
+	d.DeliveredAt = cloneTime(d.DeliveredAt)
+	return d
+}
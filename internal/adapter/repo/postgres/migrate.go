@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockKey — ключ advisory-блокировки: миграции не применяются одновременно
+// несколькими экземплярами сервиса
+const migrationLockKey int64 = 0x70725f6d6967 // "pr_mig"
+
+// Migration — версия схемы: SQL применения и (если есть) отката
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus — миграция и момент её применения (nil — не применена)
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator применяет миграции и ведёт учёт версий в таблице schema_migrations
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator читает миграции из dir файловой системы fsys: NNN_имя.sql — применение,
+// NNN_имя.down.sql — откат
+func NewMigrator(db *DB, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: db.pool, migrations: migrations}, nil
+}
+
+// Up применяет все непримененные миграции по возрастанию версии; каждая — в своей транзакции
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if _, ok := done[mg.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mg.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					mg.Version,
+					mg.Name,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s: %w", mg.Version, mg.Name, err)
+			}
+			applied = append(applied, mg)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := done[mg.Version]; !ok {
+				continue
+			}
+			if mg.Down == "" {
+				return fmt.Errorf("migration %03d_%s has no down script", mg.Version, mg.Name)
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mg.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mg.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s: %w", mg.Version, mg.Name, err)
+			}
+			reverted = append(reverted, mg)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status — все известные миграции с отметкой о применении
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var res []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			st := MigrationStatus{Migration: mg}
+			if at, ok := done[mg.Version]; ok {
+				st.AppliedAt = &at
+			}
+			res = append(res, st)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// withLock выполняет fn на отдельном соединении под advisory-блокировкой миграций
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return err
+	}
+	defer func() {
+		// блокировка сессионная: снимаем её, даже если контекст уже отменён
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}()
+
+	if _, err := conn.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+		     version    BIGINT      PRIMARY KEY,
+		     name       TEXT        NOT NULL,
+		     applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		 )`,
+	); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		res[version] = at
+	}
+	return res, rows.Err()
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		base, isDown := strings.CutSuffix(e.Name(), ".down.sql")
+		if !isDown {
+			base = strings.TrimSuffix(e.Name(), ".sql")
+		}
+		num, name, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(num, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %q: name must look like NNN_name.sql", e.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: name}
+			byVersion[version] = mg
+		}
+		if mg.Name != name {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, mg.Name, name)
+		}
+		if isDown {
+			mg.Down = string(body)
+		} else {
+			mg.Up = string(body)
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s: missing up script", mg.Version, mg.Name)
+		}
+		res = append(res, *mg)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+	return res, nil
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"prservice/internal/domain"
+)
+
+type NotificationRepo struct {
+	db *DB
+}
+
+func NewNotificationRepo(db *DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+const notificationColumns = `id, event_id, user_id, channel, recipient, subject, body,
+		        status, attempts, COALESCE(last_error, ''), next_attempt_at, created_at, sent_at`
+
+func (r *NotificationRepo) GetPreferences(
+	ctx context.Context,
+	userID domain.UserID,
+) (*domain.NotificationPreferences, error) {
+	var (
+		p        domain.NotificationPreferences
+		channels []string
+		events   []string
+	)
+	err := r.db.pool.QueryRow(ctx,
+		`SELECT user_id, email, chat_handle, channels, events
+		   FROM notification_preferences
+		  WHERE user_id = $1`,
+		string(userID),
+	).Scan(&p.UserID, &p.Email, &p.ChatHandle, &channels, &events)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range channels {
+		p.Channels = append(p.Channels, domain.NotificationChannel(c))
+	}
+	for _, e := range events {
+		p.Events = append(p.Events, domain.EventType(e))
+	}
+	return &p, nil
+}
+
+func (r *NotificationRepo) SetPreferences(ctx context.Context, p domain.NotificationPreferences) error {
+	channels := make([]string, len(p.Channels))
+	for i, c := range p.Channels {
+		channels[i] = string(c)
+	}
+
+	_, err := r.db.pool.Exec(ctx,
+		`INSERT INTO notification_preferences (user_id, email, chat_handle, channels, events)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id) DO UPDATE
+		    SET email = EXCLUDED.email,
+		        chat_handle = EXCLUDED.chat_handle,
+		        channels = EXCLUDED.channels,
+		        events = EXCLUDED.events`,
+		string(p.UserID),
+		p.Email,
+		p.ChatHandle,
+		channels,
+		eventTypeStrings(p.Events),
+	)
+	return err
+}
+
+func (r *NotificationRepo) EnqueueNotifications(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, n := range notifications {
+		batch.Queue(
+			`INSERT INTO notifications
+			     (event_id, user_id, channel, recipient, subject, body, next_attempt_at, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 ON CONFLICT (event_id, user_id, channel) DO NOTHING`,
+			n.EventID,
+			string(n.UserID),
+			string(n.Channel),
+			n.To,
+			n.Subject,
+			n.Body,
+			n.NextAttemptAt,
+			n.CreatedAt,
+		)
+	}
+	return r.db.pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *NotificationRepo) ClaimNotifications(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.Notification, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`WITH claimed AS (
+		     SELECT id AS claimed_id
+		       FROM notifications
+		      WHERE status = 'PENDING' AND next_attempt_at <= $1
+		      ORDER BY id
+		      LIMIT $3
+		      FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE notifications n
+		    SET attempts = n.attempts + 1,
+		        next_attempt_at = $2
+		   FROM claimed
+		  WHERE n.id = claimed.claimed_id
+		RETURNING `+notificationColumns,
+		now,
+		leaseUntil,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		if err := rows.Scan(
+			&n.ID, &n.EventID, &n.UserID, &n.Channel, &n.To, &n.Subject, &n.Body,
+			&n.Status, &n.Attempts, &n.LastError, &n.NextAttemptAt, &n.CreatedAt, &n.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING не сохраняет порядок подзапроса
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkNotificationSent(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE notifications
+		    SET status = 'DELIVERED',
+		        last_error = NULL,
+		        sent_at = $2
+		  WHERE id = $1`,
+		id,
+		at,
+	)
+	return err
+}
+
+func (r *NotificationRepo) MarkNotificationFailed(
+	ctx context.Context,
+	id int64,
+	reason string,
+	nextAttemptAt time.Time,
+	dead bool,
+) error {
+	status := domain.DeliveryPending
+	if dead {
+		status = domain.DeliveryDead
+	}
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE notifications
+		    SET status = $2,
+		        last_error = $3,
+		        next_attempt_at = $4
+		  WHERE id = $1 AND status = 'PENDING'`,
+		id,
+		string(status),
+		reason,
+		nextAttemptAt,
+	)
+	return err
+}
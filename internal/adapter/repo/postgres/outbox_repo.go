@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"prservice/internal/domain"
+)
+
+type OutboxRepo struct {
+	db *DB
+}
+
+func NewOutboxRepo(db *DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (t *prTx) SaveEvents(ctx context.Context, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(
+			`INSERT INTO outbox (event_type, team_name, payload, occurred_at)
+			 VALUES ($1, NULLIF($2, ''), $3, $4)`,
+			string(e.Type),
+			string(e.Team),
+			[]byte(e.Payload),
+			e.OccurredAt,
+		)
+	}
+
+	br := t.tx.SendBatch(ctx, batch)
+	for range events {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return err
+		}
+	}
+	return br.Close()
+}
+
+// ClaimPending: SKIP LOCKED — параллельные relay не ждут друг друга и не берут одни и те же события
+func (r *OutboxRepo) ClaimPending(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.OutboxEntry, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`WITH claimed AS (
+		     SELECT id
+		       FROM outbox
+		      WHERE status = 'PENDING' AND next_attempt_at <= $1
+		      ORDER BY id
+		      LIMIT $3
+		      FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE outbox o
+		    SET attempts = o.attempts + 1,
+		        next_attempt_at = $2
+		   FROM claimed
+		  WHERE o.id = claimed.id
+		RETURNING o.id, o.event_type, COALESCE(o.team_name, ''), o.payload, o.occurred_at, o.attempts`,
+		now,
+		leaseUntil,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.OutboxEntry
+	for rows.Next() {
+		var (
+			e       domain.OutboxEntry
+			payload []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Type, &e.Team, &payload, &e.OccurredAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		res = append(res, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING не сохраняет порядок подзапроса
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkDelivered(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE outbox
+		    SET status = 'DELIVERED',
+		        delivered_at = $2,
+		        last_error = NULL
+		  WHERE id = $1`,
+		id,
+		at,
+	)
+	return err
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE outbox
+		    SET last_error = $2,
+		        next_attempt_at = $3
+		  WHERE id = $1 AND status = 'PENDING'`,
+		id,
+		reason,
+		nextAttemptAt,
+	)
+	return err
+}
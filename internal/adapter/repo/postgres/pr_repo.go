@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -41,7 +44,7 @@ func (r *PRRepo) WithTx(ctx context.Context, fn func(tx domain.PRTx) error) erro
 
 func (r *PRRepo) GetByID(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
 	row := r.db.pool.QueryRow(ctx,
-		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, COALESCE(team_name, '')
 		   FROM pull_requests
 		  WHERE pull_request_id = $1`,
 		string(id),
@@ -56,40 +59,50 @@ func (r *PRRepo) GetByID(ctx context.Context, id domain.PullRequestID) (*domain.
 	}
 
 	// подгружаем ревьюверов
-	reviewers, err := r.loadReviewers(ctx, id)
+	reviews, err := r.loadReviewers(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	pr.AssignedReviewers = reviewers
+	applyReviews(pr, reviews)
 
 	return pr, nil
 }
 
-func (r *PRRepo) Create(ctx context.Context, pr domain.PullRequest) error {
+func (r *PRRepo) Create(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
 	_, err := r.db.pool.Exec(ctx,
-		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, team_name)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
 		string(pr.ID),
 		pr.Name,
 		string(pr.AuthorID),
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.ClosedAt,
+		string(pr.TeamName),
 	)
+	if isUniqueViolation(err, "pull_requests_pkey") {
+		return domain.ErrPRExists
+	}
 	if err != nil {
 		return err
 	}
-	return r.saveReviewers(ctx, pr.ID, pr.AssignedReviewers)
+	if err := saveChangedFiles(ctx, r.db.pool, pr.ID, pr.ChangedFiles); err != nil {
+		return err
+	}
+	return r.saveReviewers(ctx, pr.ID, pr.AssignedReviewers, assignedAt)
 }
 
-func (r *PRRepo) Update(ctx context.Context, pr domain.PullRequest) error {
+func (r *PRRepo) Update(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
 	_, err := r.db.pool.Exec(ctx,
 		`UPDATE pull_requests
 		    SET pull_request_name = $2,
 		        author_id = $3,
 		        status = $4,
 		        created_at = $5,
-		        merged_at = $6
+		        merged_at = $6,
+		        closed_at = $7,
+		        team_name = NULLIF($8, '')
 		  WHERE pull_request_id = $1`,
 		string(pr.ID),
 		pr.Name,
@@ -97,20 +110,22 @@ func (r *PRRepo) Update(ctx context.Context, pr domain.PullRequest) error {
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.ClosedAt,
+		string(pr.TeamName),
 	)
 	if err != nil {
 		return err
 	}
-	return r.saveReviewers(ctx, pr.ID, pr.AssignedReviewers)
+	return r.saveReviewers(ctx, pr.ID, pr.AssignedReviewers, assignedAt)
 }
 
 func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]domain.PullRequest, error) {
 	rows, err := r.db.pool.Query(ctx,
-		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at, COALESCE(pr.team_name, '')
 		   FROM pull_requests pr
 		   JOIN pull_request_reviewers r ON r.pull_request_id = pr.pull_request_id
 		  WHERE r.reviewer_id = $1
-		  ORDER BY pr.created_at DESC NULLS LAST`,
+		  ORDER BY pr.created_at DESC, pr.pull_request_id DESC`,
 		string(reviewerID),
 	)
 	if err != nil {
@@ -121,7 +136,7 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID domain.UserID) (
 	var res []domain.PullRequest
 	for rows.Next() {
 		var pr domain.PullRequest
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.TeamName); err != nil {
 			return nil, err
 		}
 		// для /users/getReview AssignedReviewers не требуется
@@ -130,6 +145,160 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID domain.UserID) (
 	return res, nil
 }
 
+// List — keyset-пагинация по (created_at, pull_request_id) в порядке убывания.
+// PR без created_at считаются самыми старыми.
+func (r *PRRepo) List(ctx context.Context, f domain.PRFilter) ([]domain.PullRequest, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != "" {
+		conds = append(conds, "pr.status = "+arg(string(f.Status)))
+	}
+	if f.AuthorID != "" {
+		conds = append(conds, "pr.author_id = "+arg(string(f.AuthorID)))
+	}
+	if f.TeamName != "" {
+		conds = append(conds, "pr.team_name = "+arg(string(f.TeamName)))
+	}
+	if f.ReviewerID != "" {
+		conds = append(conds, `EXISTS (
+		    SELECT 1
+		      FROM pull_request_reviewers r
+		     WHERE r.pull_request_id = pr.pull_request_id
+		       AND r.reviewer_id = `+arg(string(f.ReviewerID))+")")
+	}
+	if f.CreatedFrom != nil {
+		conds = append(conds, "pr.created_at >= "+arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		conds = append(conds, "pr.created_at < "+arg(*f.CreatedTo))
+	}
+	if f.MergedFrom != nil {
+		conds = append(conds, "pr.merged_at >= "+arg(*f.MergedFrom))
+	}
+	if f.MergedTo != nil {
+		conds = append(conds, "pr.merged_at < "+arg(*f.MergedTo))
+	}
+	if f.After != nil {
+		conds = append(conds, "(pr.created_at, pr.pull_request_id) < ("+
+			arg(f.After.CreatedAt)+", "+arg(string(f.After.ID))+")")
+	}
+
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at, COALESCE(pr.team_name, '')
+	            FROM pull_requests pr`
+	if len(conds) > 0 {
+		query += "\n WHERE " + strings.Join(conds, "\n   AND ")
+	}
+	query += "\n ORDER BY pr.created_at DESC, pr.pull_request_id DESC" +
+		"\n LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.TeamName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res = append(res, pr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]domain.PullRequestID, len(res))
+	for i := range res {
+		ids[i] = res[i].ID
+	}
+	reviews, err := loadReviewersBatch(ctx, r.db.pool, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		applyReviews(&res[i], reviews[res[i].ID])
+	}
+	return res, nil
+}
+
+// ListOverdueCandidates — команда PR без team_name — основная команда автора, как при назначении.
+// Срок в рабочих часах проверяется в SQL: для каждого встречающегося sla_hours заранее
+// считается самое позднее время назначения, ревью с которым уже просрочено.
+func (r *PRRepo) ListOverdueCandidates(ctx context.Context, now time.Time, limit int) ([]domain.OverdueReview, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT DISTINCT sla_hours FROM team_review_sla WHERE sla_hours > 0`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var hours []int
+	for rows.Next() {
+		var h int
+		if err := rows.Scan(&h); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		hours = append(hours, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(hours) == 0 {
+		return nil, nil
+	}
+	cutoffs := make([]time.Time, len(hours))
+	for i, h := range hours {
+		cutoffs[i] = domain.ReviewSLA{Hours: h}.LatestOverdueAssignment(now)
+	}
+
+	rows, err = r.db.pool.Query(ctx,
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, s.team_name,
+		        r.reviewer_id, r.assigned_at, s.sla_hours, s.policy, COALESCE(s.lead_id, '')
+		   FROM pull_request_reviewers r
+		   JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+		   JOIN users a ON a.user_id = pr.author_id
+		   JOIN team_review_sla s ON s.team_name = COALESCE(pr.team_name, a.team_name)
+		   JOIN unnest($1::int[], $2::timestamptz[]) AS c (sla_hours, overdue_by) ON c.sla_hours = s.sla_hours
+		  WHERE pr.status = 'OPEN'
+		    AND r.first_acted_at IS NULL
+		    AND r.overdue_at IS NULL
+		    AND r.assigned_at <= c.overdue_by
+		  ORDER BY r.assigned_at, pr.pull_request_id, r.reviewer_id
+		  LIMIT $3`,
+		hours,
+		cutoffs,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.OverdueReview
+	for rows.Next() {
+		var o domain.OverdueReview
+		if err := rows.Scan(
+			&o.PullRequestID, &o.PullRequestName, &o.AuthorID, &o.TeamName,
+			&o.ReviewerID, &o.AssignedAt, &o.SLA.Hours, &o.SLA.Policy, &o.SLA.LeadID,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, o)
+	}
+	return res, rows.Err()
+}
+
 // ==================== domain.PRTx ====================
 
 type prTx struct {
@@ -138,7 +307,7 @@ type prTx struct {
 
 func (t *prTx) GetByIDForUpdate(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
 	row := t.tx.QueryRow(ctx,
-		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, COALESCE(team_name, '')
 		   FROM pull_requests
 		  WHERE pull_request_id = $1
 		  FOR UPDATE`,
@@ -153,39 +322,74 @@ func (t *prTx) GetByIDForUpdate(ctx context.Context, id domain.PullRequestID) (*
 		return nil, err
 	}
 
-	reviewers, err := loadReviewersTx(ctx, t.tx, id)
+	reviews, err := loadReviewersTx(ctx, t.tx, id)
 	if err != nil {
 		return nil, err
 	}
-	pr.AssignedReviewers = reviewers
+	applyReviews(pr, reviews)
 	return pr, nil
 }
 
-func (t *prTx) Create(ctx context.Context, pr domain.PullRequest) error {
+func (t *prTx) Create(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
 	_, err := t.tx.Exec(ctx,
-		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, team_name)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
 		string(pr.ID),
 		pr.Name,
 		string(pr.AuthorID),
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.ClosedAt,
+		string(pr.TeamName),
 	)
+	if isUniqueViolation(err, "pull_requests_pkey") {
+		// PR создан параллельным запросом после проверки в сервисе
+		return domain.ErrPRExists
+	}
 	if err != nil {
 		return err
 	}
-	return saveReviewersTx(ctx, t.tx, pr.ID, pr.AssignedReviewers)
+	if err := saveChangedFiles(ctx, t.tx, pr.ID, pr.ChangedFiles); err != nil {
+		return err
+	}
+	return saveReviewersTx(ctx, t.tx, pr.ID, pr.AssignedReviewers, assignedAt)
 }
 
-func (t *prTx) Update(ctx context.Context, pr domain.PullRequest) error {
+func (t *prTx) ListChangedFiles(ctx context.Context, id domain.PullRequestID) ([]string, error) {
+	rows, err := t.tx.Query(ctx,
+		`SELECT path
+		   FROM pull_request_files
+		  WHERE pull_request_id = $1
+		  ORDER BY path`,
+		string(id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		res = append(res, path)
+	}
+	return res, rows.Err()
+}
+
+func (t *prTx) Update(ctx context.Context, pr domain.PullRequest, assignedAt time.Time) error {
 	_, err := t.tx.Exec(ctx,
 		`UPDATE pull_requests
 		    SET pull_request_name = $2,
 		        author_id = $3,
 		        status = $4,
 		        created_at = $5,
-		        merged_at = $6
+		        merged_at = $6,
+		        closed_at = $7,
+		        team_name = NULLIF($8, '')
 		  WHERE pull_request_id = $1`,
 		string(pr.ID),
 		pr.Name,
@@ -193,11 +397,324 @@ func (t *prTx) Update(ctx context.Context, pr domain.PullRequest) error {
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.ClosedAt,
+		string(pr.TeamName),
 	)
 	if err != nil {
 		return err
 	}
-	return saveReviewersTx(ctx, t.tx, pr.ID, pr.AssignedReviewers)
+	return saveReviewersTx(ctx, t.tx, pr.ID, pr.AssignedReviewers, assignedAt)
+}
+
+func (t *prTx) ListOpenByReviewersForUpdate(
+	ctx context.Context,
+	reviewerIDs []domain.UserID,
+) ([]domain.PullRequest, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := t.tx.Query(ctx,
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at, COALESCE(pr.team_name, '')
+		   FROM pull_requests pr
+		  WHERE pr.status = 'OPEN'
+		    AND EXISTS (
+		        SELECT 1
+		          FROM pull_request_reviewers r
+		         WHERE r.pull_request_id = pr.pull_request_id
+		           AND r.reviewer_id = ANY($1))
+		  ORDER BY pr.pull_request_id
+		    FOR UPDATE`,
+		userIDStrings(reviewerIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.TeamName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res = append(res, pr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// ревьюверы всех найденных PR — одним запросом
+	ids := make([]domain.PullRequestID, len(res))
+	for i := range res {
+		ids[i] = res[i].ID
+	}
+	reviews, err := loadReviewersBatch(ctx, t.tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		applyReviews(&res[i], reviews[res[i].ID])
+	}
+	return res, nil
+}
+
+func (t *prTx) ReplaceReviewers(
+	ctx context.Context,
+	changes []domain.ReviewReassignment,
+	assignedAt time.Time,
+) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, c := range changes {
+		if c.NewReviewerID == "" {
+			batch.Queue(
+				`DELETE FROM pull_request_reviewers
+				  WHERE pull_request_id = $1 AND reviewer_id = $2`,
+				string(c.PullRequestID), string(c.OldReviewerID),
+			)
+			continue
+		}
+		batch.Queue(
+			`UPDATE pull_request_reviewers
+			    SET reviewer_id = $3,
+			        review_state = 'PENDING',
+			        reviewed_at = NULL,
+			        assigned_at = $4,
+			        first_acted_at = NULL,
+			        overdue_at = NULL
+			  WHERE pull_request_id = $1 AND reviewer_id = $2`,
+			string(c.PullRequestID), string(c.OldReviewerID), string(c.NewReviewerID), assignedAt,
+		)
+	}
+
+	br := t.tx.SendBatch(ctx, batch)
+	for range changes {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return err
+		}
+	}
+	return br.Close()
+}
+
+func (t *prTx) DeactivateUsers(ctx context.Context, userIDs []domain.UserID) ([]domain.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := t.tx.Query(ctx,
+		`UPDATE users
+		    SET is_active = FALSE
+		  WHERE user_id = ANY($1)
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
+		userIDStrings(userIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
+
+func (t *prTx) SetUserIsActive(ctx context.Context, userID domain.UserID, isActive bool) (*domain.User, error) {
+	var u domain.User
+	err := t.tx.QueryRow(ctx,
+		`UPDATE users
+		    SET is_active = $2
+		  WHERE user_id = $1
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
+		string(userID),
+		isActive,
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (t *prTx) CreateTeam(ctx context.Context, team domain.Team) error {
+	return createTeam(ctx, t.tx, team)
+}
+
+func (t *prTx) UpsertUser(ctx context.Context, u domain.User) error {
+	return upsertUser(ctx, t.tx, u)
+}
+
+func (t *prTx) SetUserTeam(ctx context.Context, userID domain.UserID, team domain.TeamName) (*domain.User, error) {
+	var u domain.User
+	err := t.tx.QueryRow(ctx,
+		`UPDATE users
+		    SET team_name = NULLIF($2, '')
+		  WHERE user_id = $1
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
+		string(userID),
+		string(team),
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (t *prTx) AddTeamMember(ctx context.Context, team domain.TeamName, userID domain.UserID) error {
+	_, err := t.tx.Exec(ctx,
+		`INSERT INTO team_memberships (team_name, user_id)
+		 VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		string(team),
+		string(userID),
+	)
+	return err
+}
+
+func (t *prTx) RemoveTeamMember(ctx context.Context, team domain.TeamName, userID domain.UserID) (*domain.User, error) {
+	tag, err := t.tx.Exec(ctx,
+		`DELETE FROM team_memberships
+		  WHERE team_name = $1 AND user_id = $2`,
+		string(team),
+		string(userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	var u domain.User
+	err = t.tx.QueryRow(ctx,
+		`UPDATE users
+		    SET team_name = CASE
+		            WHEN team_name = $2 THEN (
+		                SELECT m.team_name
+		                  FROM team_memberships m
+		                 WHERE m.user_id = users.user_id
+		                 ORDER BY m.created_at, m.team_name
+		                 LIMIT 1)
+		            ELSE team_name
+		        END
+		  WHERE user_id = $1
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
+		string(userID),
+		string(team),
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (t *prTx) CountOpenPRsByTeam(ctx context.Context, team domain.TeamName) (int, error) {
+	var n int
+	err := t.tx.QueryRow(ctx,
+		`SELECT COUNT(*)
+		   FROM pull_requests pr
+		  WHERE pr.status = 'OPEN'
+		    AND (pr.team_name = $1
+		      OR EXISTS (
+		            SELECT 1
+		              FROM team_memberships m
+		             WHERE m.user_id = pr.author_id AND m.team_name = $1)
+		      OR EXISTS (
+		            SELECT 1
+		              FROM pull_request_reviewers r
+		              JOIN team_memberships m ON m.user_id = r.reviewer_id
+		             WHERE r.pull_request_id = pr.pull_request_id AND m.team_name = $1))`,
+		string(team),
+	).Scan(&n)
+	return n, err
+}
+
+func (t *prTx) DeleteTeam(ctx context.Context, team domain.TeamName) (bool, error) {
+	// основной командой участников становится другая их команда (если есть)
+	if _, err := t.tx.Exec(ctx,
+		`UPDATE users
+		    SET team_name = (
+		            SELECT m.team_name
+		              FROM team_memberships m
+		             WHERE m.user_id = users.user_id
+		               AND m.team_name <> $1
+		             ORDER BY m.created_at, m.team_name
+		             LIMIT 1)
+		  WHERE team_name = $1`,
+		string(team),
+	); err != nil {
+		return false, err
+	}
+
+	tag, err := t.tx.Exec(ctx,
+		`DELETE FROM teams WHERE team_name = $1`,
+		string(team),
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (t *prTx) SetReviewState(
+	ctx context.Context,
+	id domain.PullRequestID,
+	reviewerID domain.UserID,
+	state domain.ReviewState,
+	at time.Time,
+) error {
+	_, err := t.tx.Exec(ctx,
+		`UPDATE pull_request_reviewers
+		    SET review_state = $3,
+		        reviewed_at = $4,
+		        first_acted_at = COALESCE(first_acted_at, $4)
+		  WHERE pull_request_id = $1 AND reviewer_id = $2`,
+		string(id),
+		string(reviewerID),
+		string(state),
+		at,
+	)
+	return err
+}
+
+func (t *prTx) MarkReviewOverdue(
+	ctx context.Context,
+	id domain.PullRequestID,
+	reviewerID domain.UserID,
+	assignedAt, at time.Time,
+) (bool, error) {
+	tag, err := t.tx.Exec(ctx,
+		`UPDATE pull_request_reviewers
+		    SET overdue_at = $4
+		  WHERE pull_request_id = $1 AND reviewer_id = $2
+		    AND assigned_at = $3
+		    AND first_acted_at IS NULL
+		    AND overdue_at IS NULL`,
+		string(id),
+		string(reviewerID),
+		assignedAt,
+		at,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
 }
 
 // domain.PRRepository methods reused
@@ -213,10 +730,11 @@ func (t *prTx) GetByID(ctx context.Context, id domain.PullRequestID) (*domain.Pu
 
 func (t *prTx) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]domain.PullRequest, error) {
 	rows, err := t.tx.Query(ctx,
-		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at, COALESCE(pr.team_name, '')
 		   FROM pull_requests pr
 		   JOIN pull_request_reviewers r ON r.pull_request_id = pr.pull_request_id
-		  WHERE r.reviewer_id = $1`,
+		  WHERE r.reviewer_id = $1
+		  ORDER BY pr.created_at DESC, pr.pull_request_id DESC`,
 		string(reviewerID),
 	)
 	if err != nil {
@@ -227,7 +745,7 @@ func (t *prTx) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]
 	var res []domain.PullRequest
 	for rows.Next() {
 		var pr domain.PullRequest
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.TeamName); err != nil {
 			return nil, err
 		}
 		res = append(res, pr)
@@ -239,17 +757,18 @@ func (t *prTx) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]
 
 func scanPR(row pgx.Row) (*domain.PullRequest, error) {
 	var pr domain.PullRequest
-	if err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+	if err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.TeamName); err != nil {
 		return nil, err
 	}
 	return &pr, nil
 }
 
-func (r *PRRepo) loadReviewers(ctx context.Context, id domain.PullRequestID) ([]domain.UserID, error) {
+func (r *PRRepo) loadReviewers(ctx context.Context, id domain.PullRequestID) ([]domain.Review, error) {
 	rows, err := r.db.pool.Query(ctx,
-		`SELECT reviewer_id
+		`SELECT reviewer_id, review_state, reviewed_at, assigned_at, first_acted_at, overdue_at
 		   FROM pull_request_reviewers
-		  WHERE pull_request_id = $1`,
+		  WHERE pull_request_id = $1
+		  ORDER BY assigned_at, reviewer_id`,
 		string(id),
 	)
 	if err != nil {
@@ -257,29 +776,42 @@ func (r *PRRepo) loadReviewers(ctx context.Context, id domain.PullRequestID) ([]
 	}
 	defer rows.Close()
 
-	var reviewers []domain.UserID
+	var reviews []domain.Review
 	for rows.Next() {
-		var rid string
-		if err := rows.Scan(&rid); err != nil {
+		var rv domain.Review
+		if err := rows.Scan(&rv.ReviewerID, &rv.State, &rv.SubmittedAt, &rv.AssignedAt, &rv.FirstActedAt, &rv.OverdueAt); err != nil {
 			return nil, err
 		}
-		reviewers = append(reviewers, domain.UserID(rid))
+		reviews = append(reviews, rv)
 	}
-	return reviewers, nil
+	return reviews, nil
 }
 
-func (r *PRRepo) saveReviewers(ctx context.Context, id domain.PullRequestID, reviewers []domain.UserID) error {
-	// сначала очистим
+// saveReviewers синхронизирует список ревьюверов: лишние удаляются, новые добавляются
+// в состоянии PENDING с временем назначения assignedAt, состояние оставшихся сохраняется
+func (r *PRRepo) saveReviewers(
+	ctx context.Context,
+	id domain.PullRequestID,
+	reviewers []domain.UserID,
+	assignedAt time.Time,
+) error {
 	if _, err := r.db.pool.Exec(ctx,
-		`DELETE FROM pull_request_reviewers WHERE pull_request_id = $1`, string(id)); err != nil {
+		`DELETE FROM pull_request_reviewers
+		  WHERE pull_request_id = $1
+		    AND reviewer_id <> ALL($2)`,
+		string(id),
+		userIDStrings(reviewers),
+	); err != nil {
 		return err
 	}
 	for _, rid := range reviewers {
 		if _, err := r.db.pool.Exec(ctx,
-			`INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-			 VALUES ($1, $2)`,
+			`INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT DO NOTHING`,
 			string(id),
 			string(rid),
+			assignedAt,
 		); err != nil {
 			return err
 		}
@@ -287,11 +819,12 @@ func (r *PRRepo) saveReviewers(ctx context.Context, id domain.PullRequestID, rev
 	return nil
 }
 
-func loadReviewersTx(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) ([]domain.UserID, error) {
+func loadReviewersTx(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) ([]domain.Review, error) {
 	rows, err := tx.Query(ctx,
-		`SELECT reviewer_id
+		`SELECT reviewer_id, review_state, reviewed_at, assigned_at, first_acted_at, overdue_at
 		   FROM pull_request_reviewers
-		  WHERE pull_request_id = $1`,
+		  WHERE pull_request_id = $1
+		  ORDER BY assigned_at, reviewer_id`,
 		string(id),
 	)
 	if err != nil {
@@ -299,28 +832,115 @@ func loadReviewersTx(ctx context.Context, tx pgx.Tx, id domain.PullRequestID) ([
 	}
 	defer rows.Close()
 
-	var reviewers []domain.UserID
+	var reviews []domain.Review
+	for rows.Next() {
+		var rv domain.Review
+		if err := rows.Scan(&rv.ReviewerID, &rv.State, &rv.SubmittedAt, &rv.AssignedAt, &rv.FirstActedAt, &rv.OverdueAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	return reviews, nil
+}
+
+// loadReviewersBatch — ревьюверы нескольких PR одним запросом, у каждого PR — в порядке назначения
+func loadReviewersBatch(
+	ctx context.Context,
+	q querier,
+	ids []domain.PullRequestID,
+) (map[domain.PullRequestID][]domain.Review, error) {
+	res := make(map[domain.PullRequestID][]domain.Review, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = string(id)
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT pull_request_id, reviewer_id, review_state, reviewed_at, assigned_at, first_acted_at, overdue_at
+		   FROM pull_request_reviewers
+		  WHERE pull_request_id = ANY($1)
+		  ORDER BY pull_request_id, assigned_at, reviewer_id`,
+		strIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var rid string
-		if err := rows.Scan(&rid); err != nil {
+		var (
+			prID domain.PullRequestID
+			rv   domain.Review
+		)
+		if err := rows.Scan(
+			&prID, &rv.ReviewerID, &rv.State, &rv.SubmittedAt, &rv.AssignedAt, &rv.FirstActedAt, &rv.OverdueAt,
+		); err != nil {
 			return nil, err
 		}
-		reviewers = append(reviewers, domain.UserID(rid))
+		res[prID] = append(res[prID], rv)
+	}
+	return res, rows.Err()
+}
+
+// applyReviews заполняет ревьюверов PR и их состояния
+func applyReviews(pr *domain.PullRequest, reviews []domain.Review) {
+	pr.Reviews = reviews
+	pr.AssignedReviewers = make([]domain.UserID, len(reviews))
+	for i, rv := range reviews {
+		pr.AssignedReviewers[i] = rv.ReviewerID
+	}
+}
+
+func saveChangedFiles(ctx context.Context, q execer, id domain.PullRequestID, files []string) error {
+	if len(files) == 0 {
+		return nil
 	}
-	return reviewers, nil
+	_, err := q.Exec(ctx,
+		`INSERT INTO pull_request_files (pull_request_id, path)
+		 SELECT $1, unnest($2::text[])
+		 ON CONFLICT DO NOTHING`,
+		string(id),
+		files,
+	)
+	return err
 }
 
-func saveReviewersTx(ctx context.Context, tx pgx.Tx, id domain.PullRequestID, reviewers []domain.UserID) error {
+func userIDStrings(ids []domain.UserID) []string {
+	res := make([]string, len(ids))
+	for i, id := range ids {
+		res[i] = string(id)
+	}
+	return res
+}
+
+func saveReviewersTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	id domain.PullRequestID,
+	reviewers []domain.UserID,
+	assignedAt time.Time,
+) error {
 	if _, err := tx.Exec(ctx,
-		`DELETE FROM pull_request_reviewers WHERE pull_request_id = $1`, string(id)); err != nil {
+		`DELETE FROM pull_request_reviewers
+		  WHERE pull_request_id = $1
+		    AND reviewer_id <> ALL($2)`,
+		string(id),
+		userIDStrings(reviewers),
+	); err != nil {
 		return err
 	}
 	for _, rid := range reviewers {
 		if _, err := tx.Exec(ctx,
-			`INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-			 VALUES ($1, $2)`,
+			`INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT DO NOTHING`,
 			string(id),
 			string(rid),
+			assignedAt,
 		); err != nil {
 			return err
 		}
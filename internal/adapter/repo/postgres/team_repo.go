@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -17,20 +18,34 @@ func NewTeamRepo(db *DB) *TeamRepo {
 }
 
 func (r *TeamRepo) CreateTeam(ctx context.Context, team domain.Team) error {
-	_, err := r.db.pool.Exec(ctx,
-		`INSERT INTO teams (team_name) VALUES ($1)`,
+	return createTeam(ctx, r.db.pool, team)
+}
+
+func createTeam(ctx context.Context, q execer, team domain.Team) error {
+	_, err := q.Exec(ctx,
+		`INSERT INTO teams (team_name, min_reviewers, max_reviewers, required_approvals)
+		 VALUES ($1, $2, $3, $4)`,
 		string(team.Name),
+		team.Settings.MinReviewers,
+		team.Settings.MaxReviewers,
+		team.Settings.RequiredApprovals,
 	)
 	return err
 }
 
 func (r *TeamRepo) GetTeam(ctx context.Context, name domain.TeamName) (*domain.Team, error) {
 	// проверяем, есть ли команда
-	var teamName string
+	var (
+		teamName   string
+		settings   domain.TeamSettings
+		archivedAt *time.Time
+	)
 	err := r.db.pool.QueryRow(ctx,
-		`SELECT team_name FROM teams WHERE team_name = $1`,
+		`SELECT team_name, min_reviewers, max_reviewers, required_approvals, archived_at
+		   FROM teams
+		  WHERE team_name = $1`,
 		string(name),
-	).Scan(&teamName)
+	).Scan(&teamName, &settings.MinReviewers, &settings.MaxReviewers, &settings.RequiredApprovals, &archivedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -40,10 +55,11 @@ func (r *TeamRepo) GetTeam(ctx context.Context, name domain.TeamName) (*domain.T
 
 	// подгружаем участников
 	rows, err := r.db.pool.Query(ctx,
-		`SELECT user_id, username, is_active
-		   FROM users
-		  WHERE team_name = $1
-		  ORDER BY user_id`,
+		`SELECT u.user_id, u.username, u.is_active
+		   FROM team_memberships m
+		   JOIN users u ON u.user_id = m.user_id
+		  WHERE m.team_name = $1
+		  ORDER BY u.user_id`,
 		teamName,
 	)
 	if err != nil {
@@ -68,8 +84,266 @@ func (r *TeamRepo) GetTeam(ctx context.Context, name domain.TeamName) (*domain.T
 		})
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fallbacks, err := r.GetFallbacks(ctx, domain.TeamName(teamName))
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.Team{
-		Name:    domain.TeamName(teamName),
-		Members: members,
+		Name:       domain.TeamName(teamName),
+		Members:    members,
+		Settings:   settings,
+		ArchivedAt: archivedAt,
+		Fallbacks:  fallbacks,
 	}, nil
 }
+
+func (r *TeamRepo) GetSettings(ctx context.Context, name domain.TeamName) (*domain.TeamSettings, error) {
+	var settings domain.TeamSettings
+	err := r.db.pool.QueryRow(ctx,
+		`SELECT min_reviewers, max_reviewers, required_approvals
+		   FROM teams
+		  WHERE team_name = $1`,
+		string(name),
+	).Scan(&settings.MinReviewers, &settings.MaxReviewers, &settings.RequiredApprovals)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *TeamRepo) UpdateSettings(
+	ctx context.Context,
+	name domain.TeamName,
+	settings domain.TeamSettings,
+) (*domain.TeamSettings, error) {
+	var res domain.TeamSettings
+	err := r.db.pool.QueryRow(ctx,
+		`UPDATE teams
+		    SET min_reviewers = $2,
+		        max_reviewers = $3,
+		        required_approvals = $4
+		  WHERE team_name = $1
+		    AND archived_at IS NULL
+		RETURNING min_reviewers, max_reviewers, required_approvals`,
+		string(name),
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.RequiredApprovals,
+	).Scan(&res.MinReviewers, &res.MaxReviewers, &res.RequiredApprovals)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *TeamRepo) GetFallbacks(ctx context.Context, name domain.TeamName) ([]domain.TeamName, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT f.fallback_team
+		   FROM team_fallbacks f
+		   JOIN teams t ON t.team_name = f.fallback_team AND t.archived_at IS NULL
+		  WHERE f.team_name = $1
+		  ORDER BY f.priority, f.fallback_team`,
+		string(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.TeamName
+	for rows.Next() {
+		var t domain.TeamName
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (r *TeamRepo) SetFallbacks(ctx context.Context, name domain.TeamName, fallbacks []domain.TeamName) error {
+	return pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM team_fallbacks WHERE team_name = $1`,
+			string(name),
+		); err != nil {
+			return err
+		}
+		for i, f := range fallbacks {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO team_fallbacks (team_name, fallback_team, priority)
+				 VALUES ($1, $2, $3)`,
+				string(name),
+				string(f),
+				i,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TeamRepo) GetCodeOwners(ctx context.Context, name domain.TeamName) (*domain.CodeOwners, error) {
+	res := &domain.CodeOwners{}
+
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT pattern, owners
+		   FROM team_code_owners
+		  WHERE team_name = $1
+		  ORDER BY position`,
+		string(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var rule domain.CodeOwnerRule
+		if err := rows.Scan(&rule.Pattern, &rule.Owners); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res.Rules = append(res.Rules, rule)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.pool.Query(ctx,
+		`SELECT group_name, user_id
+		   FROM team_owner_groups
+		  WHERE team_name = $1
+		  ORDER BY group_name, user_id`,
+		string(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			group  string
+			userID domain.UserID
+		)
+		if err := rows.Scan(&group, &userID); err != nil {
+			return nil, err
+		}
+		if n := len(res.Groups); n == 0 || res.Groups[n-1].Name != group {
+			res.Groups = append(res.Groups, domain.CodeOwnerGroup{Name: group})
+		}
+		last := &res.Groups[len(res.Groups)-1]
+		last.Members = append(last.Members, userID)
+	}
+	return res, rows.Err()
+}
+
+func (r *TeamRepo) SetCodeOwners(ctx context.Context, name domain.TeamName, owners domain.CodeOwners) error {
+	return pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM team_code_owners WHERE team_name = $1`,
+			string(name),
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM team_owner_groups WHERE team_name = $1`,
+			string(name),
+		); err != nil {
+			return err
+		}
+
+		for i, rule := range owners.Rules {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO team_code_owners (team_name, position, pattern, owners)
+				 VALUES ($1, $2, $3, $4)`,
+				string(name),
+				i,
+				rule.Pattern,
+				rule.Owners,
+			); err != nil {
+				return err
+			}
+		}
+		for _, g := range owners.Groups {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO team_owner_groups (team_name, group_name, user_id)
+				 SELECT $1, $2, unnest($3::text[])
+				 ON CONFLICT DO NOTHING`,
+				string(name),
+				g.Name,
+				userIDStrings(g.Members),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TeamRepo) GetReviewSLA(ctx context.Context, name domain.TeamName) (*domain.ReviewSLA, error) {
+	res := &domain.ReviewSLA{Policy: domain.DefaultSLAPolicy}
+	err := r.db.pool.QueryRow(ctx,
+		`SELECT sla_hours, policy, COALESCE(lead_id, '')
+		   FROM team_review_sla
+		  WHERE team_name = $1`,
+		string(name),
+	).Scan(&res.Hours, &res.Policy, &res.LeadID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *TeamRepo) SetReviewSLA(ctx context.Context, name domain.TeamName, sla domain.ReviewSLA) error {
+	_, err := r.db.pool.Exec(ctx,
+		`INSERT INTO team_review_sla (team_name, sla_hours, policy, lead_id)
+		 VALUES ($1, $2, $3, NULLIF($4, ''))
+		 ON CONFLICT (team_name) DO UPDATE
+		    SET sla_hours = EXCLUDED.sla_hours,
+		        policy = EXCLUDED.policy,
+		        lead_id = EXCLUDED.lead_id`,
+		string(name),
+		sla.Hours,
+		string(sla.Policy),
+		string(sla.LeadID),
+	)
+	return err
+}
+
+func (r *TeamRepo) AddMember(ctx context.Context, name domain.TeamName, userID domain.UserID) error {
+	_, err := r.db.pool.Exec(ctx,
+		`INSERT INTO team_memberships (team_name, user_id)
+		 VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		string(name),
+		string(userID),
+	)
+	return err
+}
+
+func (r *TeamRepo) ArchiveTeam(ctx context.Context, name domain.TeamName, at time.Time) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx,
+		`UPDATE teams
+		    SET archived_at = COALESCE(archived_at, $2)
+		  WHERE team_name = $1`,
+		string(name),
+		at,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"prservice/internal/domain"
+)
+
+// Представление трассировки в JSONB-колонках assignment_traces
+
+type teamTraceRow struct {
+	Team             string             `json:"team"`
+	Strategy         string             `json:"strategy"`
+	Candidates       []string           `json:"candidates"`
+	Excluded         []excludedTraceRow `json:"excluded"`
+	RotationPosition string             `json:"rotation_position,omitempty"`
+}
+
+type excludedTraceRow struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+type selectedTraceRow struct {
+	ReviewerID string   `json:"reviewer_id"`
+	Reason     string   `json:"reason"`
+	Team       string   `json:"team,omitempty"`
+	Paths      []string `json:"paths,omitempty"`
+}
+
+const insertAssignmentTraceSQL = `INSERT INTO assignment_traces (pull_request_id, kind, replaced_reviewer, seed, teams, selected, created_at)
+		 VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)`
+
+func (t *prTx) SaveAssignmentTrace(ctx context.Context, trace domain.AssignmentTrace) error {
+	args, err := assignmentTraceArgs(trace)
+	if err != nil {
+		return err
+	}
+	_, err = t.tx.Exec(ctx, insertAssignmentTraceSQL, args...)
+	return err
+}
+
+func (t *prTx) SaveAssignmentTraces(ctx context.Context, traces []domain.AssignmentTrace) error {
+	if len(traces) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, trace := range traces {
+		args, err := assignmentTraceArgs(trace)
+		if err != nil {
+			return err
+		}
+		batch.Queue(insertAssignmentTraceSQL, args...)
+	}
+
+	br := t.tx.SendBatch(ctx, batch)
+	for range traces {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return err
+		}
+	}
+	return br.Close()
+}
+
+// assignmentTraceArgs — параметры insertAssignmentTraceSQL
+func assignmentTraceArgs(trace domain.AssignmentTrace) ([]any, error) {
+	teams, selected, err := marshalTrace(trace)
+	if err != nil {
+		return nil, err
+	}
+	return []any{
+		string(trace.PullRequestID),
+		string(trace.Kind),
+		string(trace.ReplacedReviewer),
+		trace.Seed,
+		teams,
+		selected,
+		trace.CreatedAt,
+	}, nil
+}
+
+func (r *PRRepo) ListAssignmentTraces(ctx context.Context, id domain.PullRequestID) ([]domain.AssignmentTrace, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT id, kind, COALESCE(replaced_reviewer, ''), seed, teams, selected, created_at
+		   FROM assignment_traces
+		  WHERE pull_request_id = $1
+		  ORDER BY id`,
+		string(id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.AssignmentTrace
+	for rows.Next() {
+		var (
+			tr       = domain.AssignmentTrace{PullRequestID: id}
+			teams    []byte
+			selected []byte
+		)
+		if err := rows.Scan(&tr.ID, &tr.Kind, &tr.ReplacedReviewer, &tr.Seed, &teams, &selected, &tr.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := unmarshalTrace(&tr, teams, selected); err != nil {
+			return nil, err
+		}
+		res = append(res, tr)
+	}
+	return res, rows.Err()
+}
+
+func marshalTrace(trace domain.AssignmentTrace) ([]byte, []byte, error) {
+	teams := make([]teamTraceRow, 0, len(trace.Teams))
+	for _, t := range trace.Teams {
+		row := teamTraceRow{
+			Team:             string(t.Team),
+			Strategy:         string(t.Strategy),
+			Candidates:       userIDStrings(t.Candidates),
+			Excluded:         make([]excludedTraceRow, 0, len(t.Excluded)),
+			RotationPosition: string(t.RotationPosition),
+		}
+		for _, e := range t.Excluded {
+			row.Excluded = append(row.Excluded, excludedTraceRow{UserID: string(e.UserID), Reason: string(e.Reason)})
+		}
+		teams = append(teams, row)
+	}
+
+	selected := make([]selectedTraceRow, 0, len(trace.Selected))
+	for _, a := range trace.Selected {
+		selected = append(selected, selectedTraceRow{
+			ReviewerID: string(a.ReviewerID),
+			Reason:     string(a.Reason),
+			Team:       string(a.Team),
+			Paths:      a.Paths,
+		})
+	}
+
+	teamsJSON, err := json.Marshal(teams)
+	if err != nil {
+		return nil, nil, err
+	}
+	selectedJSON, err := json.Marshal(selected)
+	if err != nil {
+		return nil, nil, err
+	}
+	return teamsJSON, selectedJSON, nil
+}
+
+func unmarshalTrace(tr *domain.AssignmentTrace, teamsJSON, selectedJSON []byte) error {
+	var (
+		teams    []teamTraceRow
+		selected []selectedTraceRow
+	)
+	if err := json.Unmarshal(teamsJSON, &teams); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(selectedJSON, &selected); err != nil {
+		return err
+	}
+
+	for _, row := range teams {
+		t := domain.TeamTrace{
+			Team:             domain.TeamName(row.Team),
+			Strategy:         domain.ReviewerStrategy(row.Strategy),
+			RotationPosition: domain.UserID(row.RotationPosition),
+		}
+		for _, id := range row.Candidates {
+			t.Candidates = append(t.Candidates, domain.UserID(id))
+		}
+		for _, e := range row.Excluded {
+			t.Excluded = append(t.Excluded, domain.ExcludedCandidate{
+				UserID: domain.UserID(e.UserID),
+				Reason: domain.ExclusionReason(e.Reason),
+			})
+		}
+		tr.Teams = append(tr.Teams, t)
+	}
+	for _, row := range selected {
+		tr.Selected = append(tr.Selected, domain.ReviewerAssignment{
+			ReviewerID: domain.UserID(row.ReviewerID),
+			Reason:     domain.AssignmentReason(row.Reason),
+			Team:       domain.TeamName(row.Team),
+			Paths:      row.Paths,
+		})
+	}
+	return nil
+}
@@ -2,8 +2,7 @@ package postgres
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -19,12 +18,16 @@ func NewUserRepo(db *DB) *UserRepo {
 }
 
 func (r *UserRepo) UpsertUser(ctx context.Context, u domain.User) error {
-	_, err := r.db.pool.Exec(ctx,
+	return upsertUser(ctx, r.db.pool, u)
+}
+
+func upsertUser(ctx context.Context, q execer, u domain.User) error {
+	_, err := q.Exec(ctx,
 		`INSERT INTO users (user_id, username, team_name, is_active)
-		 VALUES ($1, $2, $3, $4)
+		 VALUES ($1, $2, NULLIF($3, ''), $4)
 		 ON CONFLICT (user_id) DO UPDATE SET
 		   username = EXCLUDED.username,
-		   team_name = EXCLUDED.team_name,
+		   team_name = COALESCE(users.team_name, EXCLUDED.team_name),
 		   is_active = EXCLUDED.is_active`,
 		string(u.ID),
 		u.Username,
@@ -40,10 +43,10 @@ func (r *UserRepo) SetIsActive(ctx context.Context, userID domain.UserID, isActi
 		`UPDATE users
 		    SET is_active = $2
 		  WHERE user_id = $1
-		RETURNING user_id, username, team_name, is_active`,
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
 		string(userID),
 		isActive,
-	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -56,11 +59,11 @@ func (r *UserRepo) SetIsActive(ctx context.Context, userID domain.UserID, isActi
 func (r *UserRepo) GetByID(ctx context.Context, userID domain.UserID) (*domain.User, error) {
 	var u domain.User
 	err := r.db.pool.QueryRow(ctx,
-		`SELECT user_id, username, team_name, is_active
+		`SELECT user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews
 		   FROM users
 		  WHERE user_id = $1`,
 		string(userID),
-	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -70,39 +73,136 @@ func (r *UserRepo) GetByID(ctx context.Context, userID domain.UserID) (*domain.U
 	return &u, nil
 }
 
-// ListActiveByTeamExcept — активные участники команды, кроме списка exclude
-func (r *UserRepo) ListActiveByTeamExcept(
+func (r *UserRepo) SetMaxOpenReviews(
+	ctx context.Context,
+	userID domain.UserID,
+	maxOpenReviews *int,
+) (*domain.User, error) {
+	var u domain.User
+	err := r.db.pool.QueryRow(ctx,
+		`UPDATE users
+		    SET max_open_reviews = $2
+		  WHERE user_id = $1
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews`,
+		string(userID),
+		maxOpenReviews,
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ListCandidatesWithLoad — участники неархивной команды, количество OPEN PR, где они ревьюверы,
+// и признак недоступности в момент at
+func (r *UserRepo) ListCandidatesWithLoad(
 	ctx context.Context,
 	team domain.TeamName,
-	exclude []domain.UserID,
-) ([]domain.User, error) {
-	base := `SELECT user_id, username, team_name, is_active
-	           FROM users
-	          WHERE team_name = $1
-	            AND is_active = TRUE`
-	args := []any{string(team)}
-	if len(exclude) > 0 {
-		var placeholders []string
-		for i, id := range exclude {
-			args = append(args, string(id))
-			placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
+	at time.Time,
+) ([]domain.Candidate, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT u.user_id, u.username, COALESCE(u.team_name, ''), u.is_active, u.max_open_reviews,
+		        COUNT(pr.pull_request_id),
+		        EXISTS (
+		            SELECT 1
+		              FROM user_unavailability ua
+		             WHERE ua.user_id = u.user_id
+		               AND $2 >= ua.starts_at
+		               AND $2 < ua.ends_at)
+		   FROM users u
+		   JOIN team_memberships m ON m.user_id = u.user_id
+		   JOIN teams t ON t.team_name = m.team_name AND t.archived_at IS NULL
+		   LEFT JOIN pull_request_reviewers r ON r.reviewer_id = u.user_id
+		   LEFT JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+		                             AND pr.status = 'OPEN'
+		  WHERE m.team_name = $1
+		  GROUP BY u.user_id
+		  ORDER BY u.user_id`,
+		string(team),
+		at,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Candidate
+	for rows.Next() {
+		var c domain.Candidate
+		if err := rows.Scan(
+			&c.User.ID, &c.User.Username, &c.User.TeamName, &c.User.IsActive, &c.User.MaxOpenReviews,
+			&c.OpenReviews, &c.Unavailable,
+		); err != nil {
+			return nil, err
 		}
-		base += " AND user_id NOT IN (" + strings.Join(placeholders, ",") + ")"
+		res = append(res, c)
 	}
+	return res, nil
+}
+
+// ==================== недоступность ====================
 
-	rows, err := r.db.pool.Query(ctx, base, args...)
+func (r *UserRepo) AddUnavailability(ctx context.Context, u domain.Unavailability) (*domain.Unavailability, error) {
+	row := r.db.pool.QueryRow(ctx,
+		`INSERT INTO user_unavailability (user_id, starts_at, ends_at, reason)
+		 VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, starts_at, ends_at, reason`,
+		string(u.UserID),
+		u.StartsAt,
+		u.EndsAt,
+		string(u.Reason),
+	)
+	return scanUnavailability(row)
+}
+
+func (r *UserRepo) ListUnavailability(ctx context.Context, userID domain.UserID) ([]domain.Unavailability, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT id, user_id, starts_at, ends_at, reason
+		   FROM user_unavailability
+		  WHERE user_id = $1
+		  ORDER BY starts_at`,
+		string(userID),
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var res []domain.User
+	var res []domain.Unavailability
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		u, err := scanUnavailability(rows)
+		if err != nil {
 			return nil, err
 		}
-		res = append(res, u)
+		res = append(res, *u)
 	}
 	return res, nil
 }
+
+func (r *UserRepo) DeleteUnavailability(ctx context.Context, id domain.UnavailabilityID) (*domain.Unavailability, error) {
+	row := r.db.pool.QueryRow(ctx,
+		`DELETE FROM user_unavailability
+		  WHERE id = $1
+		RETURNING id, user_id, starts_at, ends_at, reason`,
+		int64(id),
+	)
+	u, err := scanUnavailability(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func scanUnavailability(row pgx.Row) (*domain.Unavailability, error) {
+	var u domain.Unavailability
+	if err := row.Scan(&u.ID, &u.UserID, &u.StartsAt, &u.EndsAt, &u.Reason); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"prservice/internal/domain"
+)
+
+type WebhookRepo struct {
+	db *DB
+}
+
+func NewWebhookRepo(db *DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, team_name, payload, occurred_at,
+		        status, attempts, COALESCE(response_status, 0), COALESCE(last_error, ''),
+		        next_attempt_at, created_at, delivered_at`
+
+func (r *WebhookRepo) CreateSubscription(
+	ctx context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	row := r.db.pool.QueryRow(ctx,
+		`INSERT INTO webhook_subscriptions (team_name, url, secret, events, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, team_name, url, secret, events, created_at`,
+		string(sub.Team),
+		sub.URL,
+		sub.Secret,
+		eventTypeStrings(sub.Events),
+		sub.CreatedAt,
+	)
+	return scanSubscription(row)
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id domain.WebhookID) (*domain.WebhookSubscription, error) {
+	row := r.db.pool.QueryRow(ctx,
+		`SELECT id, team_name, url, secret, events, created_at
+		   FROM webhook_subscriptions
+		  WHERE id = $1`,
+		int64(id),
+	)
+	sub, err := scanSubscription(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return sub, err
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context, team domain.TeamName) ([]domain.WebhookSubscription, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`SELECT id, team_name, url, secret, events, created_at
+		   FROM webhook_subscriptions
+		  WHERE $1 = '' OR team_name = $1
+		  ORDER BY id`,
+		string(team),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *sub)
+	}
+	return res, rows.Err()
+}
+
+func (r *WebhookRepo) UpdateSubscription(
+	ctx context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	row := r.db.pool.QueryRow(ctx,
+		`UPDATE webhook_subscriptions
+		    SET url = $2,
+		        secret = $3,
+		        events = $4
+		  WHERE id = $1
+		RETURNING id, team_name, url, secret, events, created_at`,
+		int64(sub.ID),
+		sub.URL,
+		sub.Secret,
+		eventTypeStrings(sub.Events),
+	)
+	res, err := scanSubscription(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return res, err
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id domain.WebhookID) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx,
+		`DELETE FROM webhook_subscriptions WHERE id = $1`,
+		int64(id),
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *WebhookRepo) EnqueueDeliveries(
+	ctx context.Context,
+	e domain.Event,
+	subs []domain.WebhookID,
+	at time.Time,
+) error {
+	ids := make([]int64, len(subs))
+	for i, id := range subs {
+		ids[i] = int64(id)
+	}
+
+	_, err := r.db.pool.Exec(ctx,
+		`INSERT INTO webhook_deliveries
+		     (subscription_id, event_id, event_type, team_name, payload, occurred_at, next_attempt_at, created_at)
+		 SELECT s.id, $2, $3, $4, $5, $6, $7, $7
+		   FROM webhook_subscriptions s
+		  WHERE s.id = ANY($1)
+		 ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		ids,
+		e.ID,
+		string(e.Type),
+		string(e.Team),
+		[]byte(e.Payload),
+		e.OccurredAt,
+		at,
+	)
+	return err
+}
+
+func (r *WebhookRepo) ClaimDeliveries(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`WITH claimed AS (
+		     SELECT id AS claimed_id
+		       FROM webhook_deliveries
+		      WHERE status = 'PENDING' AND next_attempt_at <= $1
+		      ORDER BY id
+		      LIMIT $3
+		      FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE webhook_deliveries d
+		    SET attempts = d.attempts + 1,
+		        next_attempt_at = $2
+		   FROM claimed
+		  WHERE d.id = claimed.claimed_id
+		RETURNING `+deliveryColumns,
+		now,
+		leaseUntil,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING не сохраняет порядок подзапроса
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, attempt domain.WebhookAttempt) error {
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		    SET status = 'DELIVERED',
+		        response_status = NULLIF($2, 0),
+		        last_error = NULL,
+		        delivered_at = $3
+		  WHERE id = $1`,
+		id,
+		attempt.ResponseStatus,
+		attempt.At,
+	)
+	return err
+}
+
+func (r *WebhookRepo) MarkFailed(
+	ctx context.Context,
+	id int64,
+	attempt domain.WebhookAttempt,
+	nextAttemptAt time.Time,
+	dead bool,
+) error {
+	status := domain.DeliveryPending
+	if dead {
+		status = domain.DeliveryDead
+	}
+	_, err := r.db.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		    SET status = $2,
+		        response_status = NULLIF($3, 0),
+		        last_error = $4,
+		        next_attempt_at = $5
+		  WHERE id = $1 AND status = 'PENDING'`,
+		id,
+		string(status),
+		attempt.ResponseStatus,
+		attempt.Error,
+		nextAttemptAt,
+	)
+	return err
+}
+
+func (r *WebhookRepo) ListDeliveries(
+	ctx context.Context,
+	f domain.WebhookDeliveryFilter,
+) ([]domain.WebhookDelivery, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if f.SubscriptionID != 0 {
+		args = append(args, int64(f.SubscriptionID))
+		where = append(where, fmt.Sprintf("subscription_id = $%d", len(args)))
+	}
+	if f.Status != "" {
+		args = append(args, string(f.Status))
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	args = append(args, f.Limit)
+	query += fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+func (r *WebhookRepo) RequeueDelivery(ctx context.Context, id int64, at time.Time) (*domain.WebhookDelivery, error) {
+	rows, err := r.db.pool.Query(ctx,
+		`UPDATE webhook_deliveries
+		    SET status = 'PENDING',
+		        attempts = 0,
+		        next_attempt_at = $2
+		  WHERE id = $1 AND status = 'DEAD'
+		RETURNING `+deliveryColumns,
+		id,
+		at,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := scanDeliveries(rows)
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	var (
+		sub    domain.WebhookSubscription
+		events []string
+	)
+	if err := row.Scan(&sub.ID, &sub.Team, &sub.URL, &sub.Secret, &events, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		sub.Events = append(sub.Events, domain.EventType(e))
+	}
+	return &sub, nil
+}
+
+func scanDeliveries(rows pgx.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var res []domain.WebhookDelivery
+	for rows.Next() {
+		var (
+			d       domain.WebhookDelivery
+			payload []byte
+		)
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.Event.ID, &d.Event.Type, &d.Event.Team, &payload, &d.Event.OccurredAt,
+			&d.Status, &d.Attempts, &d.ResponseStatus, &d.LastError,
+			&d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		d.Event.Payload = payload
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+func eventTypeStrings(types []domain.EventType) []string {
+	res := make([]string, len(types))
+	for i, t := range types {
+		res[i] = string(t)
+	}
+	return res
+}
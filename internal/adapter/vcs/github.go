@@ -0,0 +1,90 @@
+// Package vcs — разбор входящих вебхуков GitHub и GitLab о pull/merge request.
+//
+// Парсер проверяет подлинность запроса и приводит событие к domain.VCSPullRequestEvent.
+// Событие, которое не меняет состояние PR (ping, правка описания, новые коммиты), разбирается в nil.
+package vcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"prservice/internal/adapter/webhook"
+	"prservice/internal/domain"
+)
+
+const (
+	HeaderGitHubEvent     = "X-GitHub-Event"
+	HeaderGitHubSignature = "X-Hub-Signature-256"
+)
+
+// GitHub — парсер событий pull_request; подпись — HMAC-SHA256 тела с секретом вебхука
+type GitHub struct {
+	secret string
+}
+
+func NewGitHub(secret string) *GitHub {
+	return &GitHub{secret: secret}
+}
+
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int64  `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		Owner    struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+func (g *GitHub) Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error) {
+	// без секрета запросы не принимаются: иначе PR мог бы создать кто угодно
+	if g.secret == "" || !webhook.Verify(g.secret, body, header.Get(HeaderGitHubSignature)) {
+		return nil, domain.ErrInvalidSignature
+	}
+	if header.Get(HeaderGitHubEvent) != "pull_request" {
+		return nil, nil
+	}
+
+	var ev githubPullRequestEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("github: decode pull_request event: %w", err)
+	}
+
+	var action domain.VCSAction
+	switch ev.Action {
+	case "opened":
+		action = domain.VCSOpened
+	case "ready_for_review":
+		action = domain.VCSReadyForReview
+	case "reopened":
+		action = domain.VCSReopened
+	case "closed":
+		action = domain.VCSClosed
+		if ev.PullRequest.Merged {
+			action = domain.VCSMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	return &domain.VCSPullRequestEvent{
+		Provider:   domain.VCSGitHub,
+		Action:     action,
+		Repository: ev.Repository.FullName,
+		Number:     ev.PullRequest.Number,
+		Title:      ev.PullRequest.Title,
+		Draft:      ev.PullRequest.Draft,
+		Author:     ev.PullRequest.User.Login,
+		Owner:      ev.Repository.Owner.Login,
+	}, nil
+}
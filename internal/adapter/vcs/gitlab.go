@@ -0,0 +1,104 @@
+package vcs
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"prservice/internal/domain"
+)
+
+const (
+	HeaderGitLabEvent = "X-Gitlab-Event"
+	HeaderGitLabToken = "X-Gitlab-Token"
+)
+
+// GitLab — парсер событий Merge Request Hook; GitLab не подписывает тело,
+// а передаёт секретный токен вебхука в заголовке X-Gitlab-Token
+type GitLab struct {
+	token string
+}
+
+func NewGitLab(token string) *GitLab {
+	return &GitLab{token: token}
+}
+
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	// User — кто выполнил действие; для action=open это автор MR
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int64  `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+func (g *GitLab) Parse(header http.Header, body []byte) (*domain.VCSPullRequestEvent, error) {
+	token := header.Get(HeaderGitLabToken)
+	if g.token == "" || subtle.ConstantTimeCompare([]byte(g.token), []byte(token)) != 1 {
+		return nil, domain.ErrInvalidSignature
+	}
+	if header.Get(HeaderGitLabEvent) != "Merge Request Hook" {
+		return nil, nil
+	}
+
+	var ev gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("gitlab: decode merge request event: %w", err)
+	}
+	if ev.ObjectKind != "merge_request" {
+		return nil, nil
+	}
+
+	var action domain.VCSAction
+	switch ev.ObjectAttributes.Action {
+	case "open":
+		action = domain.VCSOpened
+	case "reopen":
+		action = domain.VCSReopened
+	case "close":
+		action = domain.VCSClosed
+	case "merge":
+		action = domain.VCSMerged
+	case "update":
+		// снятие пометки draft приходит как update с changes.draft
+		if d := ev.Changes.Draft; d == nil || !d.Previous || d.Current {
+			return nil, nil
+		}
+		action = domain.VCSReadyForReview
+	default:
+		return nil, nil
+	}
+
+	repo := ev.Project.PathWithNamespace
+	owner := ""
+	if i := strings.LastIndex(repo, "/"); i > 0 {
+		owner = repo[:i]
+	}
+
+	return &domain.VCSPullRequestEvent{
+		Provider:   domain.VCSGitLab,
+		Action:     action,
+		Repository: repo,
+		Number:     ev.ObjectAttributes.IID,
+		Title:      ev.ObjectAttributes.Title,
+		Draft:      ev.ObjectAttributes.Draft,
+		Author:     ev.User.Username,
+		Owner:      owner,
+	}, nil
+}
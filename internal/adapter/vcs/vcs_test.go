@@ -0,0 +1,87 @@
+package vcs
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"prservice/internal/adapter/webhook"
+	"prservice/internal/domain"
+)
+
+func TestGitHubParse(t *testing.T) {
+	body := []byte(`{
+		"action": "closed",
+		"pull_request": {"number": 12, "title": "Fix login", "merged": true, "user": {"login": "octocat"}},
+		"repository": {"full_name": "acme/api", "owner": {"login": "acme"}}
+	}`)
+	header := http.Header{}
+	header.Set(HeaderGitHubEvent, "pull_request")
+	header.Set(HeaderGitHubSignature, webhook.Sign("s3cret", body))
+
+	ev, err := NewGitHub("s3cret").Parse(header, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.VCSPullRequestEvent{
+		Provider:   domain.VCSGitHub,
+		Action:     domain.VCSMerged,
+		Repository: "acme/api",
+		Number:     12,
+		Title:      "Fix login",
+		Author:     "octocat",
+		Owner:      "acme",
+	}
+	if ev == nil || *ev != want {
+		t.Fatalf("event = %+v, want %+v", ev, want)
+	}
+	if id := ev.PullRequestID(); id != "github:acme/api#12" {
+		t.Errorf("PullRequestID = %q", id)
+	}
+
+	if _, err := NewGitHub("other").Parse(header, body); !errors.Is(err, domain.ErrInvalidSignature) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	if _, err := NewGitHub("").Parse(header, body); !errors.Is(err, domain.ErrInvalidSignature) {
+		t.Errorf("empty secret: err = %v", err)
+	}
+
+	header.Set(HeaderGitHubEvent, "ping")
+	if ev, err := NewGitHub("s3cret").Parse(header, body); ev != nil || err != nil {
+		t.Errorf("ping = %+v, %v; want nil", ev, err)
+	}
+}
+
+func TestGitLabParse(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderGitLabEvent, "Merge Request Hook")
+	header.Set(HeaderGitLabToken, "tok")
+
+	ready := []byte(`{
+		"object_kind": "merge_request",
+		"user": {"username": "jdoe"},
+		"project": {"path_with_namespace": "platform/billing/api"},
+		"object_attributes": {"iid": 7, "title": "Add invoices", "action": "update", "draft": false},
+		"changes": {"draft": {"previous": true, "current": false}}
+	}`)
+	ev, err := NewGitLab("tok").Parse(header, ready)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev == nil || ev.Action != domain.VCSReadyForReview || ev.Owner != "platform/billing" || ev.Author != "jdoe" {
+		t.Fatalf("event = %+v", ev)
+	}
+	if id := ev.PullRequestID(); id != "gitlab:platform/billing/api!7" {
+		t.Errorf("PullRequestID = %q", id)
+	}
+
+	// правка без смены draft не меняет PR
+	edit := []byte(`{"object_kind": "merge_request", "object_attributes": {"iid": 7, "action": "update"}}`)
+	if ev, err := NewGitLab("tok").Parse(header, edit); ev != nil || err != nil {
+		t.Errorf("update = %+v, %v; want nil", ev, err)
+	}
+
+	if _, err := NewGitLab("other").Parse(header, ready); !errors.Is(err, domain.ErrInvalidSignature) {
+		t.Errorf("wrong token: err = %v", err)
+	}
+}
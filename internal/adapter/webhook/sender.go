@@ -0,0 +1,95 @@
+// Package webhook — отправка доменных событий подписчикам по HTTP.
+//
+// Тело запроса — JSON события, подпись — HMAC-SHA256 тела с секретом подписки
+// в заголовке X-PR-Service-Signature-256 (формат "sha256=<hex>", как у GitHub).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+const (
+	HeaderEvent     = "X-PR-Service-Event"
+	HeaderDelivery  = "X-PR-Service-Delivery"
+	HeaderSignature = "X-PR-Service-Signature-256"
+)
+
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender — отправитель с ограничением времени на один запрос
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Body — тело запроса подписчику; ID события одинаков во всех повторах, по нему получатель
+// отбрасывает дубликаты
+type Body struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Team       string          `json:"team"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+func (s *Sender) Send(ctx context.Context, sub domain.WebhookSubscription, d domain.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(Body{
+		ID:         d.Event.ID,
+		Type:       string(d.Event.Type),
+		Team:       string(d.Event.Team),
+		OccurredAt: d.Event.OccurredAt,
+		Payload:    d.Event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(d.Event.Type))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// дочитываем ответ (не больше 64 КБ), чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook: unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign — значение заголовка подписи для тела body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет заголовок подписи за постоянное время
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+var _ domain.WebhookSender = (*Sender)(nil)
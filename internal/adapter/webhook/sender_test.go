@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+func TestSenderSignsBody(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer srv.Close()
+
+	sub := domain.WebhookSubscription{ID: 1, Team: "backend", URL: srv.URL, Secret: "s3cret"}
+	d := domain.WebhookDelivery{
+		ID:             7,
+		SubscriptionID: 1,
+		Event: domain.Event{
+			ID:         42,
+			Type:       domain.EventPRMerged,
+			Team:       "backend",
+			Payload:    json.RawMessage(`{"pull_request_id":"pr-1"}`),
+			OccurredAt: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	status, err := NewSender(time.Second).Send(context.Background(), sub, d)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Send = %d, %v", status, err)
+	}
+
+	req := <-got
+	if !Verify("s3cret", req.body, req.header.Get(HeaderSignature)) {
+		t.Errorf("signature %q does not match body", req.header.Get(HeaderSignature))
+	}
+	if Verify("other", req.body, req.header.Get(HeaderSignature)) {
+		t.Error("signature verified with a wrong secret")
+	}
+	if req.header.Get(HeaderEvent) != string(domain.EventPRMerged) || req.header.Get(HeaderDelivery) != "7" {
+		t.Errorf("headers = %v", req.header)
+	}
+
+	var body Body
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.ID != 42 || body.Type != string(domain.EventPRMerged) || string(body.Payload) != `{"pull_request_id":"pr-1"}` {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestSenderRejectsNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sub := domain.WebhookSubscription{URL: srv.URL, Secret: "s"}
+	status, err := NewSender(time.Second).Send(context.Background(), sub, domain.WebhookDelivery{})
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Fatalf("Send = %d, %v; want 503 with error", status, err)
+	}
+}
@@ -2,35 +2,131 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"prservice/internal/config"
+	"prservice/internal/domain"
 	"prservice/internal/usecase"
+	"prservice/internal/usecase/selector"
+	"prservice/internal/adapter/eventlog"
+	"prservice/internal/adapter/vcs"
+	"prservice/internal/adapter/webhook"
+	"prservice/internal/adapter/repo/memory"
 	"prservice/internal/adapter/repo/postgres"
 	httpadapter "prservice/internal/adapter/http"
 )
 
 func Run(cfg config.Config) error {
-	// Инициализация БД (Postgres адаптер)
-	db, err := postgres.New(cfg.DB.DSN)
+	// SIGINT/SIGTERM запускают остановку; повторный сигнал завершает процесс сразу
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		teamRepo domain.TeamRepository
+		userRepo domain.UserRepository
+		prRepo   domain.PRRepository
+		outbox   domain.OutboxRepository
+		webhooks domain.WebhookRepository
+		notifyDB domain.NotificationRepository
+		closeDB  func()
+	)
+	if cfg.DB.InMemory {
+		// Хранилище в памяти: данные живут до остановки процесса
+		db := memory.New()
+		teamRepo = memory.NewTeamRepo(db)
+		userRepo = memory.NewUserRepo(db)
+		prRepo = memory.NewPRRepo(db)
+		outbox = memory.NewOutboxRepo(db)
+		webhooks = memory.NewWebhookRepo(db)
+		notifyDB = memory.NewNotificationRepo(db)
+		closeDB = func() { db.Close(context.Background()) }
+	} else {
+		// Инициализация БД (Postgres адаптер)
+		db, err := postgres.New(cfg.DB.DSN)
+		if err != nil {
+			return err
+		}
+		closeDB = func() { db.Close(context.Background()) }
+
+		if err := migrateUp(ctx, db); err != nil {
+			closeDB()
+			return err
+		}
+
+		teamRepo = postgres.NewTeamRepo(db)
+		userRepo = postgres.NewUserRepo(db)
+		prRepo = postgres.NewPRRepo(db)
+		outbox = postgres.NewOutboxRepo(db)
+		webhooks = postgres.NewWebhookRepo(db)
+		notifyDB = postgres.NewNotificationRepo(db)
+	}
+	// пул закрывается последним — после HTTP-сервера и фоновых воркеров
+	defer closeDB()
+
+	// Стратегии выбора ревьюверов
+	selectors, err := newSelectorProvider(cfg.Review)
 	if err != nil {
 		return err
 	}
-	defer db.Close(context.Background())
-
-	teamRepo := postgres.NewTeamRepo(db)
-	userRepo := postgres.NewUserRepo(db)
-	prRepo := postgres.NewPRRepo(db)
 
 	// Usecases
-	teamSvc := usecase.NewTeamService(teamRepo, userRepo)
-	userSvc := usecase.NewUserService(userRepo)
-	prSvc := usecase.NewPRService(prRepo, userRepo)
+	clock := usecase.SystemClock{}
+	prSvc := usecase.NewPRService(prRepo, userRepo, teamRepo, selectors, usecase.NewRandSource(), clock)
+	teamSvc := usecase.NewTeamService(teamRepo, userRepo, prRepo, prSvc, clock)
+	userSvc := usecase.NewUserService(userRepo, teamRepo, prRepo, prSvc, clock)
+	webhookSvc := usecase.NewWebhookService(webhooks, teamRepo, webhook.NewSender(cfg.Webhook.Timeout), clock,
+		usecase.WebhookConfig{
+			PollInterval: cfg.Webhook.PollInterval,
+			BatchSize:    cfg.Webhook.BatchSize,
+			Lease:        cfg.Webhook.Timeout + time.Minute,
+			MinBackoff:   cfg.Webhook.MinBackoff,
+			MaxBackoff:   cfg.Webhook.MaxBackoff,
+			MaxAttempts:  cfg.Webhook.MaxAttempts,
+		})
+
+	notifySvc, err := newNotificationService(cfg.Notify, notifyDB, userRepo, clock)
+	if err != nil {
+		return err
+	}
+	vcsSvc := usecase.NewVCSSyncService(prSvc, domain.NewIdentityMap(cfg.VCS.Identities))
+	slaSvc := usecase.NewReviewSLAService(prRepo, prSvc, clock, usecase.ReviewSLAConfig{
+		CheckInterval: cfg.SLA.CheckInterval,
+		BatchSize:     cfg.SLA.BatchSize,
+	})
+
+	// Фоновые воркеры
+	bg := newWorkers()
+
+	// события из outbox пишутся в лог и ставятся в очереди вебхуков и уведомлений
+	publisher := usecase.Publishers{eventlog.NewPublisher(os.Stdout), webhookSvc, notifySvc}
+	relay := usecase.NewOutboxRelay(outbox, publisher, clock, usecase.OutboxRelayConfig{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Lease:        time.Minute,
+		MinBackoff:   cfg.Outbox.MinBackoff,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+	})
+	bg.Go("outbox-relay", relay.Run)
+	bg.Go("webhook-delivery", webhookSvc.Run)
+	bg.Go("notifications", notifySvc.Run)
+	bg.Go("review-sla", slaSvc.Run)
 
 	// HTTP сервер (оapi-codegen router подключим в adapter/http)
-	server := httpadapter.NewServer(teamSvc, userSvc, prSvc, prRepo)
-	router := httpadapter.NewRouter(server)
+	var ready atomic.Bool
+	server := httpadapter.NewServer(
+		teamSvc, userSvc, prSvc, webhookSvc, vcsSvc, notifySvc,
+		vcs.NewGitHub(cfg.VCS.GitHubSecret), vcs.NewGitLab(cfg.VCS.GitLabToken),
+		prRepo,
+	)
+	router := httpadapter.NewRouter(server, &ready)
 
 	srv := &http.Server{
 		Addr:         cfg.HTTP.Addr,
@@ -39,5 +135,59 @@ func Run(cfg config.Config) error {
 		WriteTimeout: 5 * time.Second,
 	}
 
-	return srv.ListenAndServe()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	ready.Store(true)
+
+	select {
+	case err := <-serveErr:
+		// сервер не запустился (например, порт занят)
+		_ = bg.Stop(context.Background())
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("shutting down")
+	return shutdown(cfg.HTTP, &ready, srv, bg)
+}
+
+// shutdown останавливает компоненты по порядку: сначала /ready начинает отвечать 503,
+// затем HTTP-сервер дожидается активных запросов (и их транзакций), затем воркеры.
+// У воркеров свой срок: долгий HTTP-дренаж не отнимает у них время на завершение.
+// Пул БД закрывает вызывающий.
+func shutdown(cfg config.HTTPConfig, ready *atomic.Bool, srv *http.Server, bg *workers) error {
+	ready.Store(false)
+	time.Sleep(cfg.ReadinessDelay)
+
+	var errs []error
+
+	httpCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(httpCtx); err != nil {
+		// не дождались: рвём оставшиеся соединения, их транзакции откатятся
+		_ = srv.Close()
+		errs = append(errs, fmt.Errorf("http shutdown: %w", err))
+	}
+
+	bgCtx, cancel := context.WithTimeout(context.Background(), cfg.WorkersShutdownTimeout)
+	defer cancel()
+	if err := bg.Stop(bgCtx); err != nil {
+		errs = append(errs, fmt.Errorf("workers shutdown: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func newSelectorProvider(cfg config.ReviewConfig) (*selector.Provider, error) {
+	byTeam := make(map[domain.TeamName]domain.ReviewerStrategy, len(cfg.TeamStrategies))
+	for team, strategy := range cfg.TeamStrategies {
+		byTeam[domain.TeamName(team)] = domain.ReviewerStrategy(strategy)
+	}
+	weights := make(map[domain.UserID]int, len(cfg.Weights))
+	for id, w := range cfg.Weights {
+		weights[domain.UserID(id)] = w
+	}
+	return selector.NewProvider(domain.ReviewerStrategy(cfg.Strategy), byTeam, weights)
 }
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"prservice/internal/adapter/repo/postgres"
+	"prservice/internal/config"
+	dbmigrations "prservice/internal/db"
+)
+
+// Migrate выполняет подкоманду migrate: up | down [N] | status
+func Migrate(ctx context.Context, cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [N] | status")
+	}
+
+	db, err := postgres.New(cfg.DB.DSN)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	m, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mg := range applied {
+			fmt.Printf("applied  %03d_%s\n", mg.Version, mg.Name)
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("migrate down: invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, mg := range reverted {
+			fmt.Printf("reverted %03d_%s\n", mg.Version, mg.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%03d_%-28s %s\n", st.Version, st.Name, applied)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("migrate: unknown command %q (want up, down or status)", args[0])
+	}
+}
+
+// migrateUp применяет непримененные миграции при старте сервиса
+func migrateUp(ctx context.Context, db *postgres.DB) error {
+	m, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(ctx)
+	for _, mg := range applied {
+		log.Printf("migration %03d_%s applied", mg.Version, mg.Name)
+	}
+	return err
+}
+
+func newMigrator(db *postgres.DB) (*postgres.Migrator, error) {
+	return postgres.NewMigrator(db, dbmigrations.Migrations, "migrations")
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"prservice/internal/adapter/notify"
+	"prservice/internal/config"
+	"prservice/internal/domain"
+	"prservice/internal/usecase"
+)
+
+// newNotificationService — уведомления по каналам, для которых задана конфигурация
+func newNotificationService(
+	cfg config.NotifyConfig,
+	repo domain.NotificationRepository,
+	users domain.UserRepository,
+	clock usecase.Clock,
+) (*usecase.NotificationService, error) {
+	templates, err := loadNotificationTemplates(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	notifiers := make(map[domain.NotificationChannel]domain.Notifier)
+	if cfg.ChatWebhookURL != "" {
+		notifiers[domain.NotifyChat] = notify.NewChat(cfg.ChatWebhookURL, cfg.Timeout)
+	}
+	if cfg.SMTP.Addr != "" {
+		email, err := notify.NewSMTP(notify.SMTPConfig{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			Timeout:  cfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers[domain.NotifyEmail] = email
+	}
+
+	return usecase.NewNotificationService(repo, users, notifiers, templates, clock, usecase.NotificationConfig{
+		PollInterval: cfg.PollInterval,
+		BatchSize:    cfg.BatchSize,
+		Lease:        cfg.Timeout + time.Minute,
+		MinBackoff:   cfg.MinBackoff,
+		MaxBackoff:   cfg.MaxBackoff,
+		MaxAttempts:  cfg.MaxAttempts,
+	}), nil
+}
+
+// loadNotificationTemplates читает из dir файлы <тип события>.tmpl; отсутствующие берутся встроенными
+func loadNotificationTemplates(dir string) (usecase.NotificationTemplates, error) {
+	overrides := make(map[domain.EventType]string)
+	if dir != "" {
+		for _, t := range domain.NotifiableEvents {
+			text, err := os.ReadFile(filepath.Join(dir, string(t)+".tmpl"))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			overrides[t] = string(text)
+		}
+	}
+	return usecase.ParseNotificationTemplates(overrides)
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// workers — фоновые процессы сервиса. Останавливаются после HTTP-сервера, чтобы
+// обработать то, что успели породить последние запросы, и до закрытия пула БД.
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+// Go запускает run в отдельной горутине; run должен вернуться после отмены ctx
+func (w *workers) Go(name string, run func(ctx context.Context) error) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if err := run(w.ctx); err != nil && w.ctx.Err() == nil {
+			log.Printf("worker %s stopped: %v", name, err)
+		}
+	}()
+}
+
+// Stop отменяет контекст воркеров и ждёт их завершения, но не дольше ctx
+func (w *workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,9 @@
+// Package db хранит SQL-миграции схемы; они встраиваются в бинарник.
+package db
+
+import "embed"
+
+// Migrations — файлы migrations/NNN_имя.sql (применение) и migrations/NNN_имя.down.sql (откат)
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
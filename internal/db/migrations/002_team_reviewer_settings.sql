@@ -0,0 +1,9 @@
+-- Количество ревьюверов на PR настраивается для каждой команды
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS min_reviewers INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_reviewers INT NOT NULL DEFAULT 2;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_reviewers_range_check;
+ALTER TABLE teams
+    ADD CONSTRAINT teams_reviewers_range_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers);
@@ -0,0 +1,4 @@
+-- Необязательный лимит открытых ревью на пользователя (NULL — без лимита)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS max_open_reviews INT
+    CHECK (max_open_reviews IS NULL OR max_open_reviews >= 0);
@@ -0,0 +1,3 @@
+-- Индексы для массовых операций по ревьюверам и командам
+CREATE INDEX IF NOT EXISTS pull_request_reviewers_reviewer_idx ON pull_request_reviewers (reviewer_id);
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
@@ -0,0 +1,5 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS review_state;
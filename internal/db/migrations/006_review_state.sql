@@ -0,0 +1,10 @@
+-- Состояние ревью у каждого назначенного ревьювера
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS review_state TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+-- Сколько APPROVED нужно для merge (0 — без ограничений)
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0
+        CHECK (required_approvals >= 0);
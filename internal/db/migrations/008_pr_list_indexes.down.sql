@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pull_requests_created_idx;
+DROP INDEX IF EXISTS pull_requests_author_idx;
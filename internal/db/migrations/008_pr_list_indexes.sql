@@ -0,0 +1,3 @@
+-- Индексы для /pullRequest/list (сортировка по created_at, фильтр по автору)
+CREATE INDEX IF NOT EXISTS pull_requests_created_idx ON pull_requests (created_at DESC, pull_request_id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_author_idx ON pull_requests (author_id);
@@ -0,0 +1,3 @@
+-- Откат не пройдёт, пока есть пользователи без команды
+ALTER TABLE users
+    ALTER COLUMN team_name SET NOT NULL;
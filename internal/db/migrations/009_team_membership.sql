@@ -0,0 +1,3 @@
+-- Пользователь может быть исключён из команды (team_name = NULL)
+ALTER TABLE users
+    ALTER COLUMN team_name DROP NOT NULL;
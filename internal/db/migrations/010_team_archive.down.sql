@@ -0,0 +1,8 @@
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS archived_at;
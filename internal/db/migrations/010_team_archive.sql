@@ -0,0 +1,12 @@
+-- Архивная команда скрыта и не участвует в назначении, история сохраняется
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- Удаление команды больше не удаляет пользователей (и их ревью) каскадом:
+-- участники остаются вне команды
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS pull_requests_team_idx;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS team_name;
+
+DROP TABLE IF EXISTS team_memberships;
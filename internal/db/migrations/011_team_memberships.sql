@@ -0,0 +1,27 @@
+-- Пользователь может состоять в нескольких командах; users.team_name — основная команда
+CREATE TABLE IF NOT EXISTS team_memberships (
+    team_name  TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS team_memberships_user_idx ON team_memberships (user_id);
+
+INSERT INTO team_memberships (team_name, user_id)
+SELECT team_name, user_id
+  FROM users
+ WHERE team_name IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- Команда, из которой назначаются ревьюверы PR (по умолчанию — основная команда автора)
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS team_name TEXT REFERENCES teams(team_name) ON DELETE SET NULL;
+
+UPDATE pull_requests pr
+   SET team_name = u.team_name
+  FROM users u
+ WHERE u.user_id = pr.author_id
+   AND pr.team_name IS NULL;
+
+CREATE INDEX IF NOT EXISTS pull_requests_team_idx ON pull_requests (team_name, created_at DESC, pull_request_id DESC);
@@ -0,0 +1,9 @@
+-- Запасные команды: из них добираются ревьюверы, если в команде не хватает кандидатов.
+-- Меньший priority — выше приоритет.
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name     TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    fallback_team TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    priority      INT  NOT NULL,
+    PRIMARY KEY (team_name, fallback_team),
+    CHECK (team_name <> fallback_team)
+);
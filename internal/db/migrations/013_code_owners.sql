@@ -0,0 +1,24 @@
+-- Правила владения кодом в стиле CODEOWNERS: для пути действует последнее подходящее правило
+-- (с наибольшим position). Владелец — user_id или "@группа".
+CREATE TABLE IF NOT EXISTS team_code_owners (
+    team_name TEXT   NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position  INT    NOT NULL,
+    pattern   TEXT   NOT NULL,
+    owners    TEXT[] NOT NULL,
+    PRIMARY KEY (team_name, position)
+);
+
+-- Подгруппы команды для правил владения кодом
+CREATE TABLE IF NOT EXISTS team_owner_groups (
+    team_name  TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    group_name TEXT NOT NULL,
+    user_id    TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_name, group_name, user_id)
+);
+
+-- Изменённые пути PR
+CREATE TABLE IF NOT EXISTS pull_request_files (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    path            TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, path)
+);
@@ -0,0 +1,14 @@
+-- Решения по назначению ревьюверов: кого рассматривали, кого отсеяли и почему,
+-- стратегия и зерно генератора случайных чисел
+CREATE TABLE IF NOT EXISTS assignment_traces (
+    id                BIGSERIAL   PRIMARY KEY,
+    pull_request_id   TEXT        NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    kind              TEXT        NOT NULL,
+    replaced_reviewer TEXT,
+    seed              BIGINT      NOT NULL,
+    teams             JSONB       NOT NULL,
+    selected          JSONB       NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS assignment_traces_pr_idx ON assignment_traces (pull_request_id, id);
@@ -0,0 +1,17 @@
+-- Transactional outbox: доменные события пишутся в транзакции изменения,
+-- relay доставляет их и отмечает результат
+CREATE TABLE IF NOT EXISTS outbox (
+    id              BIGSERIAL   PRIMARY KEY,
+    event_type      TEXT        NOT NULL,
+    team_name       TEXT,
+    payload         JSONB       NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'DELIVERED')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at, id) WHERE status = 'PENDING';
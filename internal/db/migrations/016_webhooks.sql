@@ -0,0 +1,35 @@
+-- Подписки команд на доменные события; events пустой — все события команды
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         BIGSERIAL   PRIMARY KEY,
+    team_name  TEXT        NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    url        TEXT        NOT NULL,
+    secret     TEXT        NOT NULL,
+    events     TEXT[]      NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_team_idx ON webhook_subscriptions (team_name);
+
+-- Доставки событий подписчикам: очередь с повторами и журнал. Событие копируется,
+-- чтобы журнал не зависел от хранения outbox
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL   PRIMARY KEY,
+    subscription_id BIGINT      NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id        BIGINT      NOT NULL,
+    event_type      TEXT        NOT NULL,
+    team_name       TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    response_status INT,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    delivered_at    TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at, id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id DESC);
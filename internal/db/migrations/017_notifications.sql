@@ -0,0 +1,29 @@
+-- Настройки уведомлений; пользователь без строки получает уведомления только в чат
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id     TEXT   PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email       TEXT   NOT NULL DEFAULT '',
+    chat_handle TEXT   NOT NULL DEFAULT '',
+    channels    TEXT[] NOT NULL DEFAULT '{}',
+    events      TEXT[] NOT NULL DEFAULT '{}'
+);
+
+-- Очередь уведомлений с повторами; текст готовится при постановке в очередь
+CREATE TABLE IF NOT EXISTS notifications (
+    id              BIGSERIAL   PRIMARY KEY,
+    event_id        BIGINT      NOT NULL,
+    user_id         TEXT        NOT NULL,
+    channel         TEXT        NOT NULL,
+    recipient       TEXT        NOT NULL,
+    subject         TEXT        NOT NULL,
+    body            TEXT        NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    sent_at         TIMESTAMPTZ,
+    UNIQUE (event_id, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_pending_idx ON notifications (next_attempt_at, id) WHERE status = 'PENDING';
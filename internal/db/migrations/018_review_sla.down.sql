@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS team_review_sla;
+
+DROP INDEX IF EXISTS pull_request_reviewers_awaiting_idx;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS overdue_at,
+    DROP COLUMN IF EXISTS first_acted_at,
+    DROP COLUMN IF EXISTS assigned_at;
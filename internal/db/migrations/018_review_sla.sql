@@ -0,0 +1,28 @@
+-- Когда ревьювер назначен, когда впервые отреагировал и когда по просрочке приняты меры
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at    TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS first_acted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS overdue_at     TIMESTAMPTZ;
+
+-- Для уже назначенных ревьюверов точного времени нет: берём создание PR и последнее ревью
+UPDATE pull_request_reviewers r
+   SET assigned_at = COALESCE(pr.created_at, now()),
+       first_acted_at = r.reviewed_at
+  FROM pull_requests pr
+ WHERE pr.pull_request_id = r.pull_request_id
+   AND r.assigned_at IS NULL;
+
+ALTER TABLE pull_request_reviewers
+    ALTER COLUMN assigned_at SET DEFAULT now(),
+    ALTER COLUMN assigned_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS pull_request_reviewers_awaiting_idx ON pull_request_reviewers (assigned_at)
+    WHERE first_acted_at IS NULL AND overdue_at IS NULL;
+
+-- SLA первого ревью команды; команда без строки SLA не отслеживает
+CREATE TABLE IF NOT EXISTS team_review_sla (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    sla_hours INT  NOT NULL CHECK (sla_hours >= 0),
+    policy    TEXT NOT NULL CHECK (policy IN ('NOTIFY', 'ESCALATE', 'REASSIGN')),
+    lead_id   TEXT REFERENCES users(user_id) ON DELETE SET NULL
+);
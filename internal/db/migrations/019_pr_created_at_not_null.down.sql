@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN created_at DROP DEFAULT;
@@ -0,0 +1,10 @@
+-- Дата создания обязательна: /pullRequest/list сортирует и листает по самой колонке,
+-- поэтому работают индексы pull_requests_created_idx и pull_requests_team_idx.
+-- PR без даты создания получают самую раннюю известную дату PR, иначе — время миграции.
+UPDATE pull_requests
+   SET created_at = COALESCE(LEAST(merged_at, closed_at), now())
+ WHERE created_at IS NULL;
+
+ALTER TABLE pull_requests
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN created_at SET NOT NULL;
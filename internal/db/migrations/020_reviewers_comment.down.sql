@@ -0,0 +1 @@
+COMMENT ON TABLE pull_request_reviewers IS NULL;
@@ -0,0 +1,5 @@
+-- Комментарий из 001 («0..2 на PR») устарел: число ревьюверов задаётся настройками команды
+-- (min_reviewers/max_reviewers), владельцы изменённых путей назначаются сверх max_reviewers,
+-- а после уменьшения лимита на PR может остаться больше ревьюверов, чем он разрешает.
+COMMENT ON TABLE pull_request_reviewers IS
+    'Назначенные ревьюверы PR; число — по min_reviewers/max_reviewers команды, владельцы кода сверх лимита';
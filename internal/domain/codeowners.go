@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CodeOwnerRule — правило в стиле CODEOWNERS: шаблон пути и владельцы.
+// Владелец — user_id или ссылка на группу команды вида "@имя".
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// CodeOwnerGroup — именованная подгруппа участников команды
+type CodeOwnerGroup struct {
+	Name    string
+	Members []UserID
+}
+
+// CodeOwners — правила владения кодом команды. Как и в CODEOWNERS,
+// для пути действует последнее подходящее правило.
+type CodeOwners struct {
+	Rules  []CodeOwnerRule
+	Groups []CodeOwnerGroup
+}
+
+// Validate проверяет, что шаблоны и владельцы заданы, а группы существуют
+func (c CodeOwners) Validate() error {
+	groups := make(map[string]struct{}, len(c.Groups))
+	for _, g := range c.Groups {
+		if g.Name == "" || strings.HasPrefix(g.Name, "@") || len(g.Members) == 0 {
+			return ErrInvalidCodeOwners
+		}
+		if _, ok := groups[g.Name]; ok {
+			return ErrInvalidCodeOwners
+		}
+		groups[g.Name] = struct{}{}
+	}
+
+	for _, r := range c.Rules {
+		if strings.TrimSpace(r.Pattern) == "" || len(r.Owners) == 0 {
+			return ErrInvalidCodeOwners
+		}
+		for _, o := range r.Owners {
+			name, isGroup := strings.CutPrefix(o, "@")
+			if name == "" {
+				return ErrInvalidCodeOwners
+			}
+			if _, ok := groups[name]; isGroup && !ok {
+				return ErrInvalidCodeOwners
+			}
+		}
+	}
+	return nil
+}
+
+// CodeOwnersMatcher — правила владения кодом с шаблонами, скомпилированными один раз;
+// создаётся через CodeOwners.Matcher после загрузки правил
+type CodeOwnersMatcher struct {
+	owners CodeOwners
+	// patterns — скомпилированный шаблон каждого правила; nil — шаблон ни с чем не совпадает
+	patterns []*regexp.Regexp
+}
+
+// Matcher компилирует шаблоны правил
+func (c CodeOwners) Matcher() *CodeOwnersMatcher {
+	m := &CodeOwnersMatcher{owners: c, patterns: make([]*regexp.Regexp, len(c.Rules))}
+	for i, r := range c.Rules {
+		m.patterns[i] = compilePattern(r.Pattern)
+	}
+	return m
+}
+
+// OwnersFor — владельцы пути по последнему подходящему правилу (группы раскрываются).
+// nil — у пути нет владельцев.
+func (m *CodeOwnersMatcher) OwnersFor(path string) []UserID {
+	path = NormalizePath(path)
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		if m.patterns[i] == nil || !m.patterns[i].MatchString(path) {
+			continue
+		}
+
+		seen := make(map[UserID]struct{})
+		var res []UserID
+		add := func(id UserID) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				res = append(res, id)
+			}
+		}
+		for _, o := range m.owners.Rules[i].Owners {
+			name, isGroup := strings.CutPrefix(o, "@")
+			if !isGroup {
+				add(UserID(name))
+				continue
+			}
+			for _, g := range m.owners.Groups {
+				if g.Name == name {
+					for _, id := range g.Members {
+						add(id)
+					}
+				}
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// NormalizePath приводит путь из diff к виду "dir/file" без ведущих "/" и "./"
+func NormalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	for {
+		switch {
+		case strings.HasPrefix(path, "./"):
+			path = path[2:]
+		case strings.HasPrefix(path, "/"):
+			path = path[1:]
+		default:
+			return path
+		}
+	}
+}
+
+// MatchPath сообщает, подходит ли путь под шаблон в стиле CODEOWNERS:
+//   - "*" — любые символы внутри сегмента, "?" — один символ, "**" — любое число сегментов;
+//   - "/" в начале или в середине привязывает шаблон к корню репозитория,
+//     иначе шаблон совпадает с файлом или каталогом на любой глубине;
+//   - "/" в конце — всё содержимое каталога, "/*" в конце — только файлы непосредственно в нём.
+func MatchPath(pattern, path string) bool {
+	re := compilePattern(pattern)
+	return re != nil && re.MatchString(NormalizePath(path))
+}
+
+// compilePattern переводит шаблон в регулярное выражение по правилам MatchPath;
+// nil — пустой шаблон
+func compilePattern(pattern string) *regexp.Regexp {
+	pattern = strings.TrimSpace(pattern)
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.+$")
+	case strings.HasSuffix(pattern, "/*"):
+		// "docs/*" — только файлы непосредственно в каталоге
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	// все метасимволы экранированы, выражение всегда корректно
+	return regexp.MustCompile(b.String())
+}
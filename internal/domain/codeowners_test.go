@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/app/main.go", true},
+		{"*.go", "main.gox", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"Makefile", "build/Makefile", true},
+		{"Makefile", "Makefile.bak", false},
+		{"build", "build/out/app", true}, // без "/" в конце — файл или каталог
+		{"docs/", "docs/a.md", true},
+		{"docs/", "docs/x/y.md", true},
+		{"docs/", "web/docs/a.md", true}, // "/" только в конце не привязывает к корню
+		{"docs/", "docs", false},
+		{"/docs", "docs/a.md", true},
+		{"/docs", "web/docs/a.md", false},
+		{"docs/*", "docs/a.md", true},
+		{"docs/*", "docs/x/y.md", false},
+		{"api/**/handler.go", "api/handler.go", true},
+		{"api/**/handler.go", "api/v1/users/handler.go", true},
+		{"api/**/handler.go", "web/api/handler.go", false},
+		{"/api/**", "api/v1/a.go", true},
+		{"a+b.go", "a+b.go", true},
+		{"a+b.go", "aab.go", false},
+		{"*.go", "./cmd/main.go", true},
+		{"", "main.go", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeOwnersFor(t *testing.T) {
+	owners := CodeOwners{
+		Rules: []CodeOwnerRule{
+			{Pattern: "*", Owners: []string{"u1"}},
+			{Pattern: "/api/", Owners: []string{"@backend", "u4"}},
+			{Pattern: "/api/docs/", Owners: []string{"u5"}},
+		},
+		Groups: []CodeOwnerGroup{{Name: "backend", Members: []UserID{"u2", "u3", "u4"}}},
+	}
+	m := owners.Matcher()
+
+	tests := []struct {
+		path string
+		want []UserID
+	}{
+		{"README.md", []UserID{"u1"}},
+		{"api/handler.go", []UserID{"u2", "u3", "u4"}}, // группа раскрыта, повторы убраны
+		{"api/docs/index.md", []UserID{"u5"}},          // действует последнее подходящее правило
+	}
+	for _, tt := range tests {
+		if got := m.OwnersFor(tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("OwnersFor(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if got := (CodeOwners{}).Matcher().OwnersFor("main.go"); got != nil {
+		t.Errorf("no rules: OwnersFor = %v, want nil", got)
+	}
+}
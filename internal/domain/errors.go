@@ -9,4 +9,41 @@ var (
 	ErrNotAssigned  = errors.New("reviewer is not assigned to this pr")
 	ErrNoCandidate  = errors.New("no active replacement candidate in team")
 	ErrNotFound     = errors.New("resource not found")
+
+	ErrInvalidSettings    = errors.New("invalid team settings")
+	ErrNotEnoughReviewers = errors.New("not enough active reviewers in team")
+
+	ErrAllReviewersAtCapacity = errors.New("all candidate reviewers are at capacity")
+	ErrInvalidCapacity        = errors.New("max_open_reviews must not be negative")
+
+	ErrInvalidUnavailability = errors.New("invalid unavailability range")
+
+	ErrEmptyBulkRequest = errors.New("team_name or user_ids must be provided")
+
+	ErrInvalidReviewState   = errors.New("invalid review state")
+	ErrApprovalQuorumNotMet = errors.New("approval quorum is not met")
+
+	ErrInvalidTransition = errors.New("invalid pr status transition")
+	ErrPRNotOpen         = errors.New("pr is not open")
+
+	ErrInvalidFilter = errors.New("invalid filter or cursor")
+
+	ErrNotTeamMember        = errors.New("user is not a member of this team")
+	ErrInvalidReviewsPolicy = errors.New("open_reviews must be keep or reassign")
+
+	ErrInvalidDeleteMode = errors.New("mode must be archive or hard")
+	ErrTeamHasOpenPRs    = errors.New("team has open pull requests")
+
+	ErrInvalidFallbacks = errors.New("fallback teams must be distinct and differ from the team")
+
+	ErrInvalidCodeOwners = errors.New("invalid code owners rules")
+
+	ErrInvalidWebhook = errors.New("invalid webhook: team, http(s) url, secret and known event types are required")
+
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrUnknownIdentity  = errors.New("neither pr author nor repository owner is mapped to a user")
+
+	ErrInvalidNotificationPreferences = errors.New("invalid notification preferences: known channels and events, email for EMAIL channel")
+
+	ErrInvalidReviewSLA = errors.New("invalid review sla: sla_hours must not be negative, policy is NOTIFY, ESCALATE or REASSIGN, ESCALATE needs lead_id")
 )
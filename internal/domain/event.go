@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType — тип доменного события
+type EventType string
+
+const (
+	EventPRCreated          EventType = "PRCreated"
+	EventReviewerAssigned   EventType = "ReviewerAssigned"
+	EventReviewerReassigned EventType = "ReviewerReassigned"
+	EventPRMerged           EventType = "PRMerged"
+	EventUserDeactivated    EventType = "UserDeactivated"
+	EventTeamCreated        EventType = "TeamCreated"
+	EventReviewOverdue      EventType = "ReviewOverdue"
+)
+
+func (t EventType) Valid() bool {
+	switch t {
+	case EventPRCreated, EventReviewerAssigned, EventReviewerReassigned,
+		EventPRMerged, EventUserDeactivated, EventTeamCreated, EventReviewOverdue:
+		return true
+	}
+	return false
+}
+
+// Event — доменное событие. Записывается в outbox в той же транзакции, что и изменение,
+// и доставляется не менее одного раза: получатели дедуплицируют по ID.
+type Event struct {
+	// ID — номер события в outbox (присваивается при сохранении)
+	ID   int64
+	Type EventType
+	// Team — команда, к которой относится событие (пусто — ни к какой)
+	Team       TeamName
+	Payload    json.RawMessage
+	OccurredAt time.Time
+}
+
+// OutboxEntry — событие в outbox вместе с номером текущей попытки доставки
+type OutboxEntry struct {
+	Event
+	Attempts int
+}
+
+// Полезная нагрузка событий (JSON)
+
+type PRCreatedPayload struct {
+	PullRequestID   PullRequestID `json:"pull_request_id"`
+	PullRequestName string        `json:"pull_request_name"`
+	AuthorID        UserID        `json:"author_id"`
+	Status          PRStatus      `json:"status"`
+	Reviewers       []UserID      `json:"assigned_reviewers"`
+}
+
+type ReviewerAssignedPayload struct {
+	PullRequestID   PullRequestID    `json:"pull_request_id"`
+	PullRequestName string           `json:"pull_request_name"`
+	AuthorID        UserID           `json:"author_id"`
+	ReviewerID      UserID           `json:"reviewer_id"`
+	Reason          AssignmentReason `json:"reason,omitempty"`
+}
+
+// ReviewerReassignedPayload — замена ревьювера; пустой NewReviewerID — ревьювер снят без замены
+type ReviewerReassignedPayload struct {
+	PullRequestID   PullRequestID `json:"pull_request_id"`
+	PullRequestName string        `json:"pull_request_name"`
+	AuthorID        UserID        `json:"author_id"`
+	OldReviewerID   UserID        `json:"old_reviewer_id"`
+	NewReviewerID   UserID        `json:"new_reviewer_id,omitempty"`
+}
+
+type PRMergedPayload struct {
+	PullRequestID   PullRequestID `json:"pull_request_id"`
+	PullRequestName string        `json:"pull_request_name"`
+	AuthorID        UserID        `json:"author_id"`
+	Reviewers       []UserID      `json:"assigned_reviewers"`
+	MergedAt        *time.Time    `json:"merged_at,omitempty"`
+}
+
+type UserDeactivatedPayload struct {
+	UserID   UserID   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName TeamName `json:"team_name,omitempty"`
+}
+
+type TeamCreatedPayload struct {
+	TeamName TeamName `json:"team_name"`
+	Members  []UserID `json:"members"`
+}
+
+// ReviewOverduePayload — ревьювер не отреагировал в срок SLA; EscalatedTo — лид, которому
+// сообщено о просрочке (пусто — без эскалации)
+type ReviewOverduePayload struct {
+	PullRequestID   PullRequestID `json:"pull_request_id"`
+	PullRequestName string        `json:"pull_request_name"`
+	AuthorID        UserID        `json:"author_id"`
+	ReviewerID      UserID        `json:"reviewer_id"`
+	AssignedAt      time.Time     `json:"assigned_at"`
+	DueAt           time.Time     `json:"due_at"`
+	Policy          SLAPolicy     `json:"policy"`
+	EscalatedTo     UserID        `json:"escalated_to,omitempty"`
+}
+
+func NewPRCreatedEvent(pr PullRequest, at time.Time) Event {
+	return newEvent(EventPRCreated, pr.TeamName, at, PRCreatedPayload{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		Status:          pr.Status,
+		Reviewers:       nonNilIDs(pr.AssignedReviewers),
+	})
+}
+
+func NewReviewerAssignedEvent(pr PullRequest, a ReviewerAssignment, at time.Time) Event {
+	return newEvent(EventReviewerAssigned, pr.TeamName, at, ReviewerAssignedPayload{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		ReviewerID:      a.ReviewerID,
+		Reason:          a.Reason,
+	})
+}
+
+func NewReviewerReassignedEvent(pr PullRequest, oldID, newID UserID, at time.Time) Event {
+	return newEvent(EventReviewerReassigned, pr.TeamName, at, ReviewerReassignedPayload{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		OldReviewerID:   oldID,
+		NewReviewerID:   newID,
+	})
+}
+
+func NewPRMergedEvent(pr PullRequest, at time.Time) Event {
+	return newEvent(EventPRMerged, pr.TeamName, at, PRMergedPayload{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		Reviewers:       nonNilIDs(pr.AssignedReviewers),
+		MergedAt:        pr.MergedAt,
+	})
+}
+
+func NewReviewOverdueEvent(o OverdueReview, escalatedTo UserID, at time.Time) Event {
+	return newEvent(EventReviewOverdue, o.TeamName, at, ReviewOverduePayload{
+		PullRequestID:   o.PullRequestID,
+		PullRequestName: o.PullRequestName,
+		AuthorID:        o.AuthorID,
+		ReviewerID:      o.ReviewerID,
+		AssignedAt:      o.AssignedAt,
+		DueAt:           o.DueAt(),
+		Policy:          o.SLA.Policy,
+		EscalatedTo:     escalatedTo,
+	})
+}
+
+func NewUserDeactivatedEvent(u User, at time.Time) Event {
+	return newEvent(EventUserDeactivated, u.TeamName, at, UserDeactivatedPayload{
+		UserID:   u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+	})
+}
+
+func NewTeamCreatedEvent(team Team, at time.Time) Event {
+	members := make([]UserID, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, m.UserID)
+	}
+	return newEvent(EventTeamCreated, team.Name, at, TeamCreatedPayload{
+		TeamName: team.Name,
+		Members:  members,
+	})
+}
+
+func newEvent(t EventType, team TeamName, at time.Time, payload any) Event {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		// полезная нагрузка — структуры из строк и времени, сериализация не падает
+		panic(err)
+	}
+	return Event{Type: t, Team: team, Payload: raw, OccurredAt: at}
+}
+
+// nonNilIDs — чтобы пустой список сериализовался как [], а не null
+func nonNilIDs(ids []UserID) []UserID {
+	if ids == nil {
+		return []UserID{}
+	}
+	return ids
+}
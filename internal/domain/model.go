@@ -9,8 +9,10 @@ type PullRequestID string
 type PRStatus string
 
 const (
+	PRStatusDraft  PRStatus = "DRAFT"
 	PRStatusOpen   PRStatus = "OPEN"
 	PRStatusMerged PRStatus = "MERGED"
+	PRStatusClosed PRStatus = "CLOSED"
 )
 
 type TeamMember struct {
@@ -19,16 +21,161 @@ type TeamMember struct {
 	IsActive bool
 }
 
+// Настройки команды по умолчанию
+const (
+	DefaultMinReviewers = 0
+	DefaultMaxReviewers = 2
+)
+
+// TeamSettings — параметры назначения ревьюверов в команде
+type TeamSettings struct {
+	MinReviewers int
+	MaxReviewers int
+	// RequiredApprovals — сколько APPROVED нужно для merge без force
+	RequiredApprovals int
+}
+
+func DefaultTeamSettings() TeamSettings {
+	return TeamSettings{
+		MinReviewers: DefaultMinReviewers,
+		MaxReviewers: DefaultMaxReviewers,
+	}
+}
+
+func (s TeamSettings) Validate() error {
+	if s.MinReviewers < 0 || s.MaxReviewers < s.MinReviewers {
+		return ErrInvalidSettings
+	}
+	if s.RequiredApprovals < 0 || s.RequiredApprovals > s.MaxReviewers {
+		return ErrInvalidSettings
+	}
+	return nil
+}
+
 type Team struct {
-	Name    TeamName
-	Members []TeamMember
+	Name     TeamName
+	Members  []TeamMember
+	Settings TeamSettings
+	// ArchivedAt — момент архивации; архивная команда скрыта и не участвует в назначении
+	ArchivedAt *time.Time
+	// Fallbacks — запасные команды в порядке приоритета: из них добираются ревьюверы,
+	// если участников команды не хватает до max_reviewers
+	Fallbacks []TeamName
+}
+
+// TeamDeleteMode — способ удаления команды
+type TeamDeleteMode string
+
+const (
+	// TeamDeleteArchive — команда скрывается, история PR и участники сохраняются
+	TeamDeleteArchive TeamDeleteMode = "archive"
+	// TeamDeleteHard — команда удаляется, участники остаются вне команды
+	TeamDeleteHard TeamDeleteMode = "hard"
+)
+
+func (m TeamDeleteMode) Validate() error {
+	switch m {
+	case TeamDeleteArchive, TeamDeleteHard:
+		return nil
+	}
+	return ErrInvalidDeleteMode
 }
 
 type User struct {
 	ID       UserID
 	Username string
+	// TeamName — основная команда; состоять можно в нескольких (team_memberships)
 	TeamName TeamName
 	IsActive bool
+	// MaxOpenReviews — лимит открытых ревью, nil — без лимита
+	MaxOpenReviews *int
+}
+
+// Стратегии выбора ревьюверов
+type ReviewerStrategy string
+
+const (
+	StrategyRandom      ReviewerStrategy = "random"
+	StrategyLeastLoaded ReviewerStrategy = "least_loaded"
+	StrategyRoundRobin  ReviewerStrategy = "round_robin"
+	StrategyWeighted    ReviewerStrategy = "weighted"
+)
+
+// Candidate — кандидат в ревьюверы вместе с его текущей нагрузкой
+type Candidate struct {
+	User        User
+	OpenReviews int
+	// Unavailable — пользователь сейчас в отпуске/на больничном/дежурстве
+	Unavailable bool
+}
+
+// AtCapacity — кандидат уже набрал максимум открытых ревью
+func (c Candidate) AtCapacity() bool {
+	return c.User.MaxOpenReviews != nil && c.OpenReviews >= *c.User.MaxOpenReviews
+}
+
+// Причины плановой недоступности
+type UnavailabilityReason string
+
+const (
+	UnavailabilityVacation UnavailabilityReason = "VACATION"
+	UnavailabilitySick     UnavailabilityReason = "SICK"
+	UnavailabilityOnCall   UnavailabilityReason = "ON_CALL"
+)
+
+type UnavailabilityID int64
+
+// Unavailability — интервал [StartsAt, EndsAt), когда пользователь не назначается ревьювером
+type Unavailability struct {
+	ID       UnavailabilityID
+	UserID   UserID
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   UnavailabilityReason
+}
+
+func (u Unavailability) Validate() error {
+	switch u.Reason {
+	case UnavailabilityVacation, UnavailabilitySick, UnavailabilityOnCall:
+	default:
+		return ErrInvalidUnavailability
+	}
+	if !u.EndsAt.After(u.StartsAt) {
+		return ErrInvalidUnavailability
+	}
+	return nil
+}
+
+// Состояние ревью у конкретного ревьювера
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+// Validate — состояние, которое ревьювер может выставить сам
+func (s ReviewState) Validate() error {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return nil
+	default:
+		return ErrInvalidReviewState
+	}
+}
+
+type Review struct {
+	ReviewerID  UserID
+	State       ReviewState
+	SubmittedAt *time.Time
+	// AssignedAt — когда ревьювер назначен (при замене — когда назначен новый)
+	AssignedAt time.Time
+	// FirstActedAt — первое ревью ревьювера; от него отсчитывается SLA
+	FirstActedAt *time.Time
+	// OverdueAt — когда по просроченному ревью приняты меры по политике SLA команды
+	OverdueAt *time.Time
 }
 
 type PullRequest struct {
@@ -37,6 +184,102 @@ type PullRequest struct {
 	AuthorID          UserID
 	Status            PRStatus
 	AssignedReviewers []UserID
-	CreatedAt         *time.Time
-	MergedAt          *time.Time
+	// Reviews — состояния ревью назначенных ревьюверов (только чтение,
+	// изменяются через PRTx.SetReviewState)
+	Reviews   []Review
+	CreatedAt *time.Time
+	MergedAt  *time.Time
+	ClosedAt  *time.Time
+	// TeamName — команда, из которой назначаются ревьюверы (по умолчанию основная команда автора)
+	TeamName TeamName
+	// ChangedFiles — изменённые пути; задаются при создании и учитываются правилами владения кодом
+	ChangedFiles []string
+	// Assignments — почему выбран каждый ревьювер; заполняется только при назначении
+	Assignments []ReviewerAssignment
+}
+
+// AssignmentReason — почему ревьювер назначен на PR
+type AssignmentReason string
+
+const (
+	// AssignmentCodeOwner — владелец изменённых путей
+	AssignmentCodeOwner AssignmentReason = "CODE_OWNER"
+	// AssignmentStrategy — выбран стратегией команды PR
+	AssignmentStrategy AssignmentReason = "STRATEGY"
+	// AssignmentFallbackTeam — выбран стратегией запасной команды
+	AssignmentFallbackTeam AssignmentReason = "FALLBACK_TEAM"
+)
+
+// ReviewerAssignment — объяснение выбора ревьювера
+type ReviewerAssignment struct {
+	ReviewerID UserID
+	Reason     AssignmentReason
+	// Team — команда, из участников которой выбран ревьювер
+	Team TeamName
+	// Paths — для CODE_OWNER: изменённые пути, которыми владеет ревьювер
+	Paths []string
+}
+
+// Approvals — количество ревьюверов в состоянии APPROVED
+func (pr PullRequest) Approvals() int {
+	n := 0
+	for _, rv := range pr.Reviews {
+		if rv.State == ReviewApproved {
+			n++
+		}
+	}
+	return n
+}
+
+// ReviewReassignment — замена ревьювера на PR
+type ReviewReassignment struct {
+	PullRequestID PullRequestID
+	OldReviewerID UserID
+	NewReviewerID UserID
+}
+
+// ReassignmentReport — итог переназначения открытых ревью пользователя:
+// успешные замены и PR, для которых замены не нашлось
+type ReassignmentReport struct {
+	Reassigned  []ReviewReassignment
+	NoCandidate []ReviewReassignment
+}
+
+// OpenReviewsPolicy — что делать с открытыми ревью пользователя при уходе из команды
+type OpenReviewsPolicy string
+
+const (
+	// OpenReviewsKeep — ревью остаются за пользователем
+	OpenReviewsKeep OpenReviewsPolicy = "keep"
+	// OpenReviewsReassign — ревью переназначаются на участников прежней команды
+	OpenReviewsReassign OpenReviewsPolicy = "reassign"
+)
+
+func (p OpenReviewsPolicy) Validate() error {
+	switch p {
+	case OpenReviewsKeep, OpenReviewsReassign:
+		return nil
+	}
+	return ErrInvalidReviewsPolicy
+}
+
+// PRCursor — позиция в выдаче PR (сортировка по created_at, затем по id, по убыванию)
+type PRCursor struct {
+	CreatedAt time.Time
+	ID        PullRequestID
+}
+
+// PRFilter — фильтры и пагинация списка PR; пустые поля не фильтруют
+type PRFilter struct {
+	Status      PRStatus
+	AuthorID    UserID
+	TeamName    TeamName
+	ReviewerID  UserID
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+
+	After *PRCursor
+	Limit int
 }
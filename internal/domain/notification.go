@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"net/mail"
+	"time"
+)
+
+// NotificationChannel — канал уведомлений пользователя
+type NotificationChannel string
+
+const (
+	NotifyEmail NotificationChannel = "EMAIL"
+	// NotifyChat — сообщение в общий чат (Slack/Mattermost) с упоминанием пользователя
+	NotifyChat NotificationChannel = "CHAT"
+)
+
+func (c NotificationChannel) Valid() bool {
+	switch c {
+	case NotifyEmail, NotifyChat:
+		return true
+	}
+	return false
+}
+
+// NotifiableEvents — события, о которых уведомляются пользователи
+var NotifiableEvents = []EventType{EventReviewerAssigned, EventReviewerReassigned, EventPRMerged, EventReviewOverdue}
+
+// NotificationPreferences — настройки уведомлений пользователя
+type NotificationPreferences struct {
+	UserID UserID
+	// Email — адрес для канала EMAIL
+	Email string
+	// ChatHandle — как упомянуть пользователя в чате (по умолчанию "@username")
+	ChatHandle string
+	// Channels — включённые каналы; пусто — уведомления выключены
+	Channels []NotificationChannel
+	// Events — о каких событиях уведомлять; пусто — обо всех из NotifiableEvents
+	Events []EventType
+}
+
+// DefaultNotificationPreferences — настройки пользователя, который их не задавал: только чат
+func DefaultNotificationPreferences(userID UserID) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, Channels: []NotificationChannel{NotifyChat}}
+}
+
+func (p NotificationPreferences) Validate() error {
+	for _, c := range p.Channels {
+		if !c.Valid() {
+			return ErrInvalidNotificationPreferences
+		}
+		if c == NotifyEmail && p.Email == "" {
+			return ErrInvalidNotificationPreferences
+		}
+	}
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return ErrInvalidNotificationPreferences
+		}
+	}
+	for _, t := range p.Events {
+		if !isNotifiable(t) {
+			return ErrInvalidNotificationPreferences
+		}
+	}
+	return nil
+}
+
+// Wants — нужно ли уведомить пользователя о событии типа t
+func (p NotificationPreferences) Wants(t EventType) bool {
+	if !isNotifiable(t) {
+		return false
+	}
+	if len(p.Events) == 0 {
+		return true
+	}
+	for _, e := range p.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotifiable(t EventType) bool {
+	for _, e := range NotifiableEvents {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification — уведомление одного пользователя об одном событии в одном канале;
+// текст готовится при постановке в очередь
+type Notification struct {
+	ID      int64
+	EventID int64
+	UserID  UserID
+	Channel NotificationChannel
+	// To — адрес в канале: email или упоминание в чате
+	To            string
+	Subject       string
+	Body          string
+	Status        DeliveryStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
@@ -1,30 +1,201 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"math/rand"
+	"time"
+)
 
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team Team) error
 	GetTeam(ctx context.Context, name TeamName) (*Team, error)
+	GetSettings(ctx context.Context, name TeamName) (*TeamSettings, error)
+	UpdateSettings(ctx context.Context, name TeamName, settings TeamSettings) (*TeamSettings, error)
+	// AddMember добавляет пользователя в команду (повторное добавление ничего не меняет)
+	AddMember(ctx context.Context, name TeamName, userID UserID) error
+	// GetFallbacks — запасные команды в порядке приоритета (архивные пропускаются)
+	GetFallbacks(ctx context.Context, name TeamName) ([]TeamName, error)
+	// SetFallbacks заменяет список запасных команд; приоритет — порядок в списке
+	SetFallbacks(ctx context.Context, name TeamName, fallbacks []TeamName) error
+	// GetCodeOwners — правила владения кодом команды (пустые, если не заданы)
+	GetCodeOwners(ctx context.Context, name TeamName) (*CodeOwners, error)
+	// SetCodeOwners заменяет правила и группы владения кодом команды
+	SetCodeOwners(ctx context.Context, name TeamName, owners CodeOwners) error
+	// GetReviewSLA — SLA ревью команды (нулевой, если не задан)
+	GetReviewSLA(ctx context.Context, name TeamName) (*ReviewSLA, error)
+	SetReviewSLA(ctx context.Context, name TeamName, sla ReviewSLA) error
+	// ArchiveTeam помечает команду архивной; false — команды нет
+	ArchiveTeam(ctx context.Context, name TeamName, at time.Time) (bool, error)
 }
 
 type UserRepository interface {
+	// UpsertUser создаёт или обновляет пользователя; основная команда задаётся только новому
+	// пользователю или пользователю без команды, членство в командах не меняется
 	UpsertUser(ctx context.Context, user User) error
 	SetIsActive(ctx context.Context, userID UserID, isActive bool) (*User, error)
+	SetMaxOpenReviews(ctx context.Context, userID UserID, maxOpenReviews *int) (*User, error)
 	GetByID(ctx context.Context, userID UserID) (*User, error)
-	ListActiveByTeamExcept(ctx context.Context, team TeamName, exclude []UserID) ([]User, error)
+	// ListCandidatesWithLoad — все участники команды (по team_memberships) с числом открытых (OPEN) ревью
+	// и признаком недоступности в момент at
+	ListCandidatesWithLoad(ctx context.Context, team TeamName, at time.Time) ([]Candidate, error)
+
+	AddUnavailability(ctx context.Context, u Unavailability) (*Unavailability, error)
+	ListUnavailability(ctx context.Context, userID UserID) ([]Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id UnavailabilityID) (*Unavailability, error)
 }
 
 type PRRepository interface {
 	WithTx(ctx context.Context, fn func(tx PRTx) error) error
 
 	GetByID(ctx context.Context, id PullRequestID) (*PullRequest, error)
-	Create(ctx context.Context, pr PullRequest) error
-	Update(ctx context.Context, pr PullRequest) error
+	// Create и Update сохраняют PR с ревьюверами; добавленные ревьюверы считаются назначенными в assignedAt
+	Create(ctx context.Context, pr PullRequest, assignedAt time.Time) error
+	Update(ctx context.Context, pr PullRequest, assignedAt time.Time) error
 	ListByReviewer(ctx context.Context, reviewerID UserID) ([]PullRequest, error)
+	// List — PR по фильтру (вместе с ревьюверами), от новых к старым, не больше filter.Limit
+	List(ctx context.Context, filter PRFilter) ([]PullRequest, error)
+	// ListAssignmentTraces — решения по назначению ревьюверов PR, от старых к новым
+	ListAssignmentTraces(ctx context.Context, id PullRequestID) ([]AssignmentTrace, error)
+	// ListOverdueCandidates — ревью OPEN PR, по которым ревьювер ещё ничего не сделал и меры
+	// не принимались, в командах с SLA, срок которых (ReviewSLA.DueAt) наступил не позже now
+	// (по возрастанию времени назначения, не больше limit)
+	ListOverdueCandidates(ctx context.Context, now time.Time, limit int) ([]OverdueReview, error)
 }
 
 type PRTx interface {
 	GetByIDForUpdate(ctx context.Context, id PullRequestID) (*PullRequest, error)
-	Create(ctx context.Context, pr PullRequest) error
-	Update(ctx context.Context, pr PullRequest) error
+	// Create сохраняет PR вместе с ревьюверами и изменёнными путями;
+	// ревьюверы считаются назначенными в assignedAt
+	Create(ctx context.Context, pr PullRequest, assignedAt time.Time) error
+	ListChangedFiles(ctx context.Context, id PullRequestID) ([]string, error)
+	// Update сохраняет PR; добавленные ревьюверы считаются назначенными в assignedAt
+	Update(ctx context.Context, pr PullRequest, assignedAt time.Time) error
+	// ListOpenByReviewersForUpdate — OPEN PR, где ревьювер кто-то из reviewerIDs
+	// (вместе со всеми ревьюверами), строки PR блокируются
+	ListOpenByReviewersForUpdate(ctx context.Context, reviewerIDs []UserID) ([]PullRequest, error)
+	// ReplaceReviewers применяет замены пачкой; пустой NewReviewerID — ревьювер снимается без замены.
+	// Новые ревьюверы считаются назначенными в assignedAt.
+	ReplaceReviewers(ctx context.Context, changes []ReviewReassignment, assignedAt time.Time) error
+	// SetReviewState записывает ревью; первое ревью ревьювера запоминается в FirstActedAt
+	SetReviewState(ctx context.Context, id PullRequestID, reviewerID UserID, state ReviewState, at time.Time) error
+	// MarkReviewOverdue отмечает, что по просроченному ревью приняты меры. false — ревью уже
+	// отмечено, ревьювер отреагировал или назначен заново после assignedAt
+	MarkReviewOverdue(ctx context.Context, id PullRequestID, reviewerID UserID, assignedAt, at time.Time) (bool, error)
+	SetUserIsActive(ctx context.Context, userID UserID, isActive bool) (*User, error)
+	// DeactivateUsers деактивирует пользователей и возвращает найденных
+	DeactivateUsers(ctx context.Context, userIDs []UserID) ([]User, error)
+	// SetUserTeam меняет основную команду пользователя; пустое имя — без основной команды
+	SetUserTeam(ctx context.Context, userID UserID, team TeamName) (*User, error)
+	AddTeamMember(ctx context.Context, team TeamName, userID UserID) error
+	// RemoveTeamMember исключает пользователя из команды; если она была основной, основной
+	// становится самая ранняя из оставшихся. nil — пользователь не состоял в команде
+	RemoveTeamMember(ctx context.Context, team TeamName, userID UserID) (*User, error)
+	// CountOpenPRsByTeam — число OPEN PR команды или PR, где автор или ревьювер состоит в команде
+	CountOpenPRsByTeam(ctx context.Context, team TeamName) (int, error)
+	// DeleteTeam удаляет команду вместе с членством; у участников, для которых она была
+	// основной, основной становится другая их команда. false — команды нет
+	DeleteTeam(ctx context.Context, team TeamName) (bool, error)
+	SaveAssignmentTrace(ctx context.Context, trace AssignmentTrace) error
+	// SaveAssignmentTraces — как SaveAssignmentTrace, но для нескольких решений за один запрос
+	SaveAssignmentTraces(ctx context.Context, traces []AssignmentTrace) error
+	CreateTeam(ctx context.Context, team Team) error
+	// UpsertUser — как UserRepository.UpsertUser, но в транзакции
+	UpsertUser(ctx context.Context, user User) error
+	// SaveEvents записывает доменные события в outbox
+	SaveEvents(ctx context.Context, events []Event) error
+}
+
+// OutboxRepository — очередь доменных событий для relay
+type OutboxRepository interface {
+	// ClaimPending берёт до limit недоставленных событий, для которых подошло время попытки
+	// (по возрастанию ID), увеличивает у них счётчик попыток и откладывает следующую попытку
+	// до leaseUntil — параллельный relay их пропустит. Если relay упадёт, не отметив результат,
+	// события снова станут доступны после leaseUntil.
+	ClaimPending(ctx context.Context, now, leaseUntil time.Time, limit int) ([]OutboxEntry, error)
+	MarkDelivered(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed записывает ошибку доставки и время следующей попытки
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub WebhookSubscription) (*WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id WebhookID) (*WebhookSubscription, error)
+	// ListSubscriptions — подписки команды (пусто — все), по возрастанию ID
+	ListSubscriptions(ctx context.Context, team TeamName) ([]WebhookSubscription, error)
+	// UpdateSubscription заменяет URL, секрет и фильтр событий; nil — подписки нет
+	UpdateSubscription(ctx context.Context, sub WebhookSubscription) (*WebhookSubscription, error)
+	// DeleteSubscription удаляет подписку вместе с журналом доставок; false — подписки нет
+	DeleteSubscription(ctx context.Context, id WebhookID) (bool, error)
+
+	// EnqueueDeliveries ставит событие в очередь доставки подписчикам subs. Повторная
+	// постановка того же события тому же подписчику ничего не меняет — relay outbox
+	// может опубликовать событие больше одного раза.
+	EnqueueDeliveries(ctx context.Context, event Event, subs []WebhookID, at time.Time) error
+	// ClaimDeliveries — как OutboxRepository.ClaimPending, для доставок в статусе PENDING
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, attempt WebhookAttempt) error
+	// MarkFailed записывает неудачную попытку: доставка ждёт повтора в nextAttemptAt
+	// или, если dead, переходит в DEAD
+	MarkFailed(ctx context.Context, id int64, attempt WebhookAttempt, nextAttemptAt time.Time, dead bool) error
+	// ListDeliveries — журнал доставок по фильтру, от новых к старым
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	// RequeueDelivery возвращает доставку из DEAD в PENDING со сброшенным счётчиком попыток;
+	// nil — доставки нет или она не в DEAD
+	RequeueDelivery(ctx context.Context, id int64, at time.Time) (*WebhookDelivery, error)
+}
+
+// WebhookSender отправляет доставку подписчику; status — HTTP-статус ответа (0 — ответа нет),
+// ошибка — если ответа нет или он не 2xx
+type WebhookSender interface {
+	Send(ctx context.Context, sub WebhookSubscription, delivery WebhookDelivery) (status int, err error)
+}
+
+// NotificationRepository — настройки уведомлений пользователей и очередь отправки
+type NotificationRepository interface {
+	// GetPreferences — настройки пользователя; nil — не заданы
+	GetPreferences(ctx context.Context, userID UserID) (*NotificationPreferences, error)
+	SetPreferences(ctx context.Context, p NotificationPreferences) error
+
+	// EnqueueNotifications ставит уведомления в очередь; уведомление с теми же событием,
+	// пользователем и каналом, что уже в очереди, пропускается
+	EnqueueNotifications(ctx context.Context, notifications []Notification) error
+	// ClaimNotifications — как OutboxRepository.ClaimPending, для уведомлений в статусе PENDING
+	ClaimNotifications(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64, at time.Time) error
+	// MarkNotificationFailed записывает ошибку: уведомление ждёт повтора в nextAttemptAt
+	// или, если dead, переходит в DEAD
+	MarkNotificationFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error
+}
+
+// Notifier отправляет уведомление по своему каналу
+type Notifier interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// EventPublisher доставляет доменные события за пределы сервиса
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// ReviewerSelector выбирает не более n ревьюверов из списка кандидатов.
+// Случайность берётся только из rnd, чтобы решение можно было воспроизвести по зерну.
+type ReviewerSelector interface {
+	Select(rnd *rand.Rand, team TeamName, candidates []Candidate, n int) []Candidate
+	Strategy() ReviewerStrategy
+}
+
+// RotationSelector — стратегия, которая продолжает очередь команды (round_robin), а не пользуется
+// зерном. Позиция очереди записывается в трассировку, чтобы решение можно было повторить.
+type RotationSelector interface {
+	ReviewerSelector
+	// SelectAt — как Select, но возвращает и позицию очереди, с которой начат выбор
+	// (последнего выбранного в команде до него; пусто — очередь с начала)
+	SelectAt(team TeamName, candidates []Candidate, n int) ([]Candidate, UserID)
+	// Restore ставит очередь команды на позицию position, записанную в трассировке
+	Restore(team TeamName, position UserID)
+}
+
+// ReviewerSelectorProvider возвращает стратегию выбора, настроенную для команды
+type ReviewerSelectorProvider interface {
+	ForTeam(team TeamName) ReviewerSelector
 }
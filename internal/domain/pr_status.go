@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Допустимые переходы статусов PR:
+//
+//	DRAFT  -> OPEN (готов к ревью), CLOSED
+//	OPEN   -> MERGED, CLOSED
+//	CLOSED -> OPEN (reopen)
+//	MERGED — конечное состояние
+var prTransitions = map[PRStatus][]PRStatus{
+	PRStatusDraft:  {PRStatusOpen, PRStatusClosed},
+	PRStatusOpen:   {PRStatusMerged, PRStatusClosed},
+	PRStatusClosed: {PRStatusOpen},
+}
+
+func (s PRStatus) Valid() bool {
+	switch s {
+	case PRStatusDraft, PRStatusOpen, PRStatusMerged, PRStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s PRStatus) CanTransitionTo(to PRStatus) bool {
+	for _, allowed := range prTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo переводит PR в статус to и проставляет соответствующие отметки времени.
+// При закрытии ревьюверы освобождаются.
+func (pr *PullRequest) TransitionTo(to PRStatus, at time.Time) error {
+	if !pr.Status.CanTransitionTo(to) {
+		return ErrInvalidTransition
+	}
+
+	switch to {
+	case PRStatusMerged:
+		pr.MergedAt = &at
+	case PRStatusClosed:
+		pr.ClosedAt = &at
+		pr.AssignedReviewers = nil
+		pr.Reviews = nil
+	case PRStatusOpen:
+		pr.ClosedAt = nil
+	}
+	pr.Status = to
+	return nil
+}
+
+// ErrIfNotOpen — ошибка для операций, которые разрешены только на OPEN PR
+func (pr *PullRequest) ErrIfNotOpen() error {
+	switch pr.Status {
+	case PRStatusOpen:
+		return nil
+	case PRStatusMerged:
+		return ErrPRMerged
+	default:
+		return ErrPRNotOpen
+	}
+}
@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+// SLAPolicy — что делать с ревью, по которому истёк SLA
+type SLAPolicy string
+
+const (
+	// SLANotify — напомнить ревьюверу
+	SLANotify SLAPolicy = "NOTIFY"
+	// SLAEscalate — напомнить ревьюверу и сообщить лиду команды
+	SLAEscalate SLAPolicy = "ESCALATE"
+	// SLAReassign — переназначить ревью; если замены нет — как SLAEscalate (или SLANotify без лида)
+	SLAReassign SLAPolicy = "REASSIGN"
+)
+
+// DefaultSLAPolicy — политика, если она не указана
+const DefaultSLAPolicy = SLANotify
+
+// ReviewSLA — срок первого ревью в команде. Срок считается в рабочих часах:
+// суббота и воскресенье (UTC) не учитываются, так что 24 часа — один рабочий день.
+type ReviewSLA struct {
+	// Hours — срок в рабочих часах; 0 — SLA не отслеживается
+	Hours  int
+	Policy SLAPolicy
+	// LeadID — лид команды, которому эскалируются просроченные ревью
+	LeadID UserID
+}
+
+func (s ReviewSLA) Validate() error {
+	if s.Hours < 0 {
+		return ErrInvalidReviewSLA
+	}
+	switch s.Policy {
+	case SLANotify, SLAReassign:
+	case SLAEscalate:
+		if s.LeadID == "" {
+			return ErrInvalidReviewSLA
+		}
+	default:
+		return ErrInvalidReviewSLA
+	}
+	return nil
+}
+
+func (s ReviewSLA) Enabled() bool {
+	return s.Hours > 0
+}
+
+// DueAt — срок первого ревью, назначенного в assignedAt
+func (s ReviewSLA) DueAt(assignedAt time.Time) time.Time {
+	t := assignedAt.UTC()
+	left := time.Duration(s.Hours) * time.Hour
+	for {
+		dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		next := dayStart.AddDate(0, 0, 1)
+		if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t = next
+			continue
+		}
+		rest := next.Sub(t)
+		if left <= rest {
+			return t.Add(left)
+		}
+		left -= rest
+		t = next
+	}
+}
+
+// LatestOverdueAssignment — самое позднее время назначения, ревью с которым к now уже
+// просрочено: DueAt(a) <= now тогда и только тогда, когда a <= LatestOverdueAssignment(now).
+// Позволяет отбирать просроченные ревью сравнением времени назначения.
+func (s ReviewSLA) LatestOverdueAssignment(now time.Time) time.Time {
+	t := now.UTC()
+	left := time.Duration(s.Hours) * time.Hour
+	for {
+		// день, в который шагаем назад от t; в полночь — предыдущий
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if day.Equal(t) {
+			day = day.AddDate(0, 0, -1)
+		}
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t = day
+			continue
+		}
+		rest := t.Sub(day)
+		if left <= rest {
+			return t.Add(-left)
+		}
+		left -= rest
+		t = day
+	}
+}
+
+// OverdueReview — ревью без действий ревьювера в команде с SLA
+type OverdueReview struct {
+	PullRequestID   PullRequestID
+	PullRequestName string
+	AuthorID        UserID
+	TeamName        TeamName
+	ReviewerID      UserID
+	AssignedAt      time.Time
+	SLA             ReviewSLA
+}
+
+// DueAt — срок первого ревью по SLA команды
+func (o OverdueReview) DueAt() time.Time {
+	return o.SLA.DueAt(o.AssignedAt)
+}
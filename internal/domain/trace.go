@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// AssignmentTraceKind — какое решение записано в трассировке
+type AssignmentTraceKind string
+
+const (
+	// TraceAssign — назначение ревьюверов (создание PR, markReady, reopen)
+	TraceAssign AssignmentTraceKind = "ASSIGN"
+	// TraceReassign — замена одного ревьювера
+	TraceReassign AssignmentTraceKind = "REASSIGN"
+)
+
+// ExclusionReason — почему участник команды не рассматривался как кандидат
+type ExclusionReason string
+
+const (
+	ExcludedAuthor          ExclusionReason = "AUTHOR"
+	ExcludedInactive        ExclusionReason = "INACTIVE"
+	ExcludedUnavailable     ExclusionReason = "UNAVAILABLE"
+	ExcludedAtCapacity      ExclusionReason = "AT_CAPACITY"
+	ExcludedAlreadyAssigned ExclusionReason = "ALREADY_ASSIGNED"
+)
+
+// ExcludedCandidate — участник команды, отсеянный до выбора
+type ExcludedCandidate struct {
+	UserID UserID
+	Reason ExclusionReason
+}
+
+// TeamTrace — как рассматривалась одна команда (команда PR или запасная)
+type TeamTrace struct {
+	Team     TeamName
+	Strategy ReviewerStrategy
+	// Candidates — кандидаты, из которых выбирала стратегия
+	Candidates []UserID
+	Excluded   []ExcludedCandidate
+	// RotationPosition — для round_robin: позиция очереди команды перед выбором
+	// (последний выбранный ранее; пусто — очередь с начала)
+	RotationPosition UserID
+}
+
+// AssignmentTrace — запись о решении по назначению: кого рассматривали, кого отсеяли
+// и почему, кого выбрали. Seed — зерно генератора случайных чисел, которым пользовались
+// стратегии; с теми же кандидатами и зерном выбор повторяется. round_robin зерно
+// не использует: её выбор повторяется по позиции очереди из TeamTrace.RotationPosition.
+type AssignmentTrace struct {
+	ID            int64
+	PullRequestID PullRequestID
+	Kind          AssignmentTraceKind
+	// ReplacedReviewer — для REASSIGN: кого заменяли
+	ReplacedReviewer UserID
+	Seed             int64
+	Teams            []TeamTrace
+	Selected         []ReviewerAssignment
+	CreatedAt        time.Time
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VCSProvider — внешняя система, из которой приходят события о PR
+type VCSProvider string
+
+const (
+	VCSGitHub VCSProvider = "github"
+	VCSGitLab VCSProvider = "gitlab"
+)
+
+// VCSAction — что произошло с PR во внешней системе
+type VCSAction string
+
+const (
+	VCSOpened         VCSAction = "OPENED"
+	VCSReadyForReview VCSAction = "READY_FOR_REVIEW"
+	VCSReopened       VCSAction = "REOPENED"
+	VCSMerged         VCSAction = "MERGED"
+	VCSClosed         VCSAction = "CLOSED"
+)
+
+// VCSPullRequestEvent — событие GitHub pull_request или GitLab merge request, приведённое к общему виду
+type VCSPullRequestEvent struct {
+	Provider VCSProvider
+	Action   VCSAction
+	// Repository — "owner/repo" для GitHub, "group/project" для GitLab
+	Repository string
+	Number     int64
+	Title      string
+	Draft      bool
+	// Author — логин автора PR во внешней системе
+	Author string
+	// Owner — логин владельца репозитория (пользователя, организации или группы)
+	Owner string
+}
+
+// PullRequestID — ID PR в сервисе: "github:owner/repo#12", "gitlab:group/project!12"
+func (e VCSPullRequestEvent) PullRequestID() PullRequestID {
+	sep := "#"
+	if e.Provider == VCSGitLab {
+		sep = "!"
+	}
+	return PullRequestID(fmt.Sprintf("%s:%s%s%d", e.Provider, e.Repository, sep, e.Number))
+}
+
+// IdentityMap — соответствие логинов во внешних системах пользователям сервиса.
+// Ключ — "provider:login"; логины сравниваются без учёта регистра.
+type IdentityMap map[string]UserID
+
+func NewIdentityMap(entries map[string]string) IdentityMap {
+	res := make(IdentityMap, len(entries))
+	for k, v := range entries {
+		res[strings.ToLower(k)] = UserID(v)
+	}
+	return res
+}
+
+// Resolve — пользователь сервиса для логина login в системе p
+func (m IdentityMap) Resolve(p VCSProvider, login string) (UserID, bool) {
+	if login == "" {
+		return "", false
+	}
+	id, ok := m[strings.ToLower(string(p)+":"+login)]
+	return id, ok
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"net/url"
+	"time"
+)
+
+type WebhookID int64
+
+// WebhookSubscription — подписка команды на доменные события: события команды Team,
+// тип которых есть в Events (пусто — все), отправляются POST-запросом на URL.
+// Тело подписывается HMAC-SHA256 с ключом Secret.
+type WebhookSubscription struct {
+	ID        WebhookID
+	Team      TeamName
+	URL       string
+	Secret    string
+	Events    []EventType
+	CreatedAt time.Time
+}
+
+// Matches — нужно ли отправить событие подписчику
+func (s WebhookSubscription) Matches(e Event) bool {
+	if e.Team != s.Team {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+func (s WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhook
+	}
+	if s.Team == "" || s.Secret == "" {
+		return ErrInvalidWebhook
+	}
+	for _, t := range s.Events {
+		if !t.Valid() {
+			return ErrInvalidWebhook
+		}
+	}
+	return nil
+}
+
+// DeliveryStatus — состояние доставки события подписчику
+type DeliveryStatus string
+
+const (
+	// DeliveryPending — ждёт попытки (первой или повторной)
+	DeliveryPending DeliveryStatus = "PENDING"
+	// DeliveryDelivered — получатель ответил 2xx
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	// DeliveryDead — попытки исчерпаны; вернуть в очередь можно вручную
+	DeliveryDead DeliveryStatus = "DEAD"
+)
+
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery — доставка одного события одному подписчику и её журнал
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID WebhookID
+	Event          Event
+	Status         DeliveryStatus
+	Attempts       int
+	// ResponseStatus — HTTP-статус последнего ответа (0 — ответа не было)
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookDeliveryFilter — фильтр журнала доставок; пустые поля не ограничивают выборку
+type WebhookDeliveryFilter struct {
+	SubscriptionID WebhookID
+	Status         DeliveryStatus
+	Limit          int
+}
+
+// WebhookAttempt — результат попытки доставки
+type WebhookAttempt struct {
+	ResponseStatus int
+	Error          string
+	At             time.Time
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock — источник текущего времени
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock — текущее время системы в UTC
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// NewRandSource — источник случайных чисел с зерном от текущего времени
+func NewRandSource() rand.Source {
+	return rand.NewSource(time.Now().UnixNano())
+}
+
+// lockedSource делает rand.Source безопасным для конкурентного использования
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
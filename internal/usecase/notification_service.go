@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"slices"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// NotificationConfig — параметры отправки уведомлений
+type NotificationConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease — сколько взятое уведомление недоступно другим экземплярам, пока идёт попытка
+	Lease time.Duration
+	// MinBackoff, MaxBackoff — границы экспоненциальной задержки между попытками
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxAttempts — после стольких неудачных попыток уведомление переходит в DEAD
+	MaxAttempts int
+}
+
+// NotificationService — уведомления пользователей о назначении, переназначении, merge и просроченных ревью.
+// Как domain.EventPublisher готовит уведомления по событию из outbox и ставит их в очередь;
+// Run отправляет очередь с повторами. Транзакция PR об отправке не знает и её не ждёт.
+type NotificationService struct {
+	notifications domain.NotificationRepository
+	users         domain.UserRepository
+	notifiers     map[domain.NotificationChannel]domain.Notifier
+	templates     NotificationTemplates
+	clock         Clock
+	cfg           NotificationConfig
+}
+
+// NewNotificationService — notifiers содержит только настроенные каналы: в остальные уведомления не ставятся
+func NewNotificationService(
+	notifications domain.NotificationRepository,
+	users domain.UserRepository,
+	notifiers map[domain.NotificationChannel]domain.Notifier,
+	templates NotificationTemplates,
+	clock Clock,
+	cfg NotificationConfig,
+) *NotificationService {
+	return &NotificationService{
+		notifications: notifications,
+		users:         users,
+		notifiers:     notifiers,
+		templates:     templates,
+		clock:         clock,
+		cfg:           cfg,
+	}
+}
+
+// GetPreferences — настройки пользователя (по умолчанию — только чат)
+func (s *NotificationService) GetPreferences(
+	ctx context.Context,
+	userID domain.UserID,
+) (*domain.NotificationPreferences, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.preferences(ctx, userID)
+}
+
+func (s *NotificationService) SetPreferences(
+	ctx context.Context,
+	p domain.NotificationPreferences,
+) (*domain.NotificationPreferences, error) {
+	p.Channels = slices.Clone(p.Channels)
+	slices.Sort(p.Channels)
+	p.Channels = slices.Compact(p.Channels)
+	p.Events = normalizeEventTypes(p.Events)
+	// хранится и используется в RCPT TO только адрес: "Имя <a@b>" → "a@b";
+	// некорректный адрес отклонит Validate
+	if addr, err := mail.ParseAddress(p.Email); err == nil {
+		p.Email = addr.Address
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	u, err := s.users.GetByID(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := s.notifications.SetPreferences(ctx, p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// notificationRecipient — кого уведомить о событии и в какой роли
+type notificationRecipient struct {
+	userID domain.UserID
+	role   string
+}
+
+// Publish ставит в очередь уведомления участникам PR из события
+func (s *NotificationService) Publish(ctx context.Context, e domain.Event) error {
+	recipients, err := eventRecipients(e)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	var queue []domain.Notification
+	for _, r := range recipients {
+		u, err := s.users.GetByID(ctx, r.userID)
+		if err != nil {
+			return err
+		}
+		if u == nil || !u.IsActive {
+			continue
+		}
+		prefs, err := s.preferences(ctx, r.userID)
+		if err != nil {
+			return err
+		}
+		if !prefs.Wants(e.Type) {
+			continue
+		}
+
+		subject, body, err := s.templates.render(NotificationData{Recipient: *u, Role: r.role, Event: e, Payload: payload})
+		if err != nil {
+			return err
+		}
+
+		for _, ch := range prefs.Channels {
+			if _, ok := s.notifiers[ch]; !ok {
+				continue
+			}
+			to := prefs.Email
+			if ch == domain.NotifyChat {
+				to = prefs.ChatHandle
+				if to == "" {
+					to = "@" + u.Username
+				}
+			}
+			queue = append(queue, domain.Notification{
+				EventID:       e.ID,
+				UserID:        u.ID,
+				Channel:       ch,
+				To:            to,
+				Subject:       subject,
+				Body:          body,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			})
+		}
+	}
+	return s.notifications.EnqueueNotifications(ctx, queue)
+}
+
+// Run отправляет очередь уведомлений, пока не отменён ctx
+func (s *NotificationService) Run(ctx context.Context) error {
+	poll(ctx, "notifications", s.cfg.PollInterval, s.cfg.BatchSize, s.SendOnce)
+	return nil
+}
+
+// SendOnce берёт одну пачку уведомлений и отправляет их; возвращает размер пачки.
+// Неудачная попытка откладывает уведомление с экспоненциальной задержкой, после MaxAttempts
+// уведомление переходит в DEAD.
+func (s *NotificationService) SendOnce(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	list, err := s.notifications.ClaimNotifications(ctx, now, now.Add(s.cfg.Lease), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range list {
+		if ctx.Err() != nil {
+			return len(list), nil
+		}
+
+		var sendErr error
+		if notifier, ok := s.notifiers[n.Channel]; ok {
+			sendErr = notifier.Send(ctx, n)
+		} else {
+			// канал отключили в конфигурации, пока уведомление ждало в очереди
+			sendErr = fmt.Errorf("notification channel %s is not configured", n.Channel)
+		}
+		if sendErr != nil && ctx.Err() != nil {
+			return len(list), nil
+		}
+
+		at := s.clock.Now()
+		if sendErr == nil {
+			err = s.notifications.MarkNotificationSent(ctx, n.ID, at)
+		} else {
+			next := at.Add(backoff(n.Attempts, s.cfg.MinBackoff, s.cfg.MaxBackoff))
+			err = s.notifications.MarkNotificationFailed(ctx, n.ID, sendErr.Error(), next, n.Attempts >= s.cfg.MaxAttempts)
+		}
+		if err != nil {
+			return len(list), err
+		}
+	}
+	return len(list), nil
+}
+
+func (s *NotificationService) preferences(
+	ctx context.Context,
+	userID domain.UserID,
+) (*domain.NotificationPreferences, error) {
+	p, err := s.notifications.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		def := domain.DefaultNotificationPreferences(userID)
+		p = &def
+	}
+	return p, nil
+}
+
+// eventRecipients — кого уведомить о событии; для остальных событий — никого
+func eventRecipients(e domain.Event) ([]notificationRecipient, error) {
+	switch e.Type {
+	case domain.EventReviewerAssigned:
+		var p domain.ReviewerAssignedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, err
+		}
+		return []notificationRecipient{{userID: p.ReviewerID, role: "assigned"}}, nil
+
+	case domain.EventReviewerReassigned:
+		var p domain.ReviewerReassignedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, err
+		}
+		res := []notificationRecipient{{userID: p.OldReviewerID, role: "unassigned"}}
+		if p.NewReviewerID != "" {
+			res = append(res, notificationRecipient{userID: p.NewReviewerID, role: "assigned"})
+		}
+		return res, nil
+
+	case domain.EventPRMerged:
+		var p domain.PRMergedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, err
+		}
+		res := []notificationRecipient{{userID: p.AuthorID, role: "author"}}
+		for _, id := range p.Reviewers {
+			if id != p.AuthorID {
+				res = append(res, notificationRecipient{userID: id, role: "reviewer"})
+			}
+		}
+		return res, nil
+
+	case domain.EventReviewOverdue:
+		var p domain.ReviewOverduePayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, err
+		}
+		res := []notificationRecipient{{userID: p.ReviewerID, role: "overdue"}}
+		if p.EscalatedTo != "" && p.EscalatedTo != p.ReviewerID {
+			res = append(res, notificationRecipient{userID: p.EscalatedTo, role: "lead"})
+		}
+		return res, nil
+	}
+	return nil, nil
+}
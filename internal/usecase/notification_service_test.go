@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"prservice/internal/adapter/repo/memory"
+	"prservice/internal/domain"
+)
+
+// fakeNotifier запоминает отправленные уведомления; пока fail — отказывает
+type fakeNotifier struct {
+	fail bool
+	sent []domain.Notification
+}
+
+func (f *fakeNotifier) Send(_ context.Context, n domain.Notification) error {
+	if f.fail {
+		return errors.New("unavailable")
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestNotificationsOnReassignment(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.New()
+	teams, users := memory.NewTeamRepo(db), memory.NewUserRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range teamUsers("u1", "u2", "u3") {
+		if err := users.UpsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := ParseNotificationTemplates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat, email := &fakeNotifier{}, &fakeNotifier{}
+	clock := &fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)}
+	svc := NewNotificationService(memory.NewNotificationRepo(db), users,
+		map[domain.NotificationChannel]domain.Notifier{domain.NotifyChat: chat, domain.NotifyEmail: email},
+		templates, clock, NotificationConfig{BatchSize: 10, Lease: time.Minute, MinBackoff: time.Second, MaxBackoff: time.Minute, MaxAttempts: 3},
+	)
+
+	// u3 — только почта; u2 о переназначениях не уведомляется
+	prefs, err := svc.SetPreferences(ctx, domain.NotificationPreferences{
+		UserID: "u3", Email: "U3 <u3@example.com>", Channels: []domain.NotificationChannel{domain.NotifyEmail},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Email != "u3@example.com" {
+		t.Errorf("email = %q, want bare address", prefs.Email)
+	}
+	if _, err := svc.SetPreferences(ctx, domain.NotificationPreferences{
+		UserID: "u2", Channels: []domain.NotificationChannel{domain.NotifyChat}, Events: []domain.EventType{domain.EventPRMerged},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetPreferences(ctx, domain.NotificationPreferences{
+		UserID: "u1", Channels: []domain.NotificationChannel{domain.NotifyEmail},
+	}); !errors.Is(err, domain.ErrInvalidNotificationPreferences) {
+		t.Errorf("EMAIL without address: err = %v", err)
+	}
+
+	pr := domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1", TeamName: "backend"}
+	reassigned := domain.NewReviewerReassignedEvent(pr, "u2", "u3", clock.now)
+	reassigned.ID = 10
+	for range 2 {
+		// повторная публикация того же события не дублирует уведомления
+		if err := svc.Publish(ctx, reassigned); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// первая попытка почты неудачна и откладывается
+	email.fail = true
+	if n, err := svc.SendOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("SendOnce = %d, %v", n, err)
+	}
+	email.fail = false
+	if n, _ := svc.SendOnce(ctx); n != 0 {
+		t.Fatalf("retry before backoff: claimed %d", n)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if n, err := svc.SendOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("SendOnce after backoff = %d, %v", n, err)
+	}
+
+	if len(chat.sent) != 0 || len(email.sent) != 1 {
+		t.Fatalf("chat = %+v, email = %+v", chat.sent, email.sent)
+	}
+	n := email.sent[0]
+	if n.UserID != "u3" || n.To != "u3@example.com" || n.Subject != "Вас назначили ревьювером pr-1" {
+		t.Errorf("notification = %+v", n)
+	}
+	if !strings.Contains(n.Body, "вместо u2") {
+		t.Errorf("body = %q", n.Body)
+	}
+
+	// merge: автору — в чат по умолчанию, ревьюверу u2 — по его настройкам
+	pr.AssignedReviewers = []domain.UserID{"u2"}
+	merged := domain.NewPRMergedEvent(pr, clock.now)
+	merged.ID = 11
+	if err := svc.Publish(ctx, merged); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SendOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(chat.sent) != 2 || chat.sent[0].To != "@u1" || chat.sent[1].To != "@u2" {
+		t.Fatalf("chat = %+v", chat.sent)
+	}
+	if !strings.Contains(chat.sent[1].Body, "ваше ревью больше не нужно") || strings.Contains(chat.sent[0].Body, "ревью") {
+		t.Errorf("merged bodies: %q, %q", chat.sent[0].Body, chat.sent[1].Body)
+	}
+}
+
+func TestNotificationsOnReviewOverdue(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.New()
+	teams, users := memory.NewTeamRepo(db), memory.NewUserRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range teamUsers("u1", "u2", "lead") {
+		if err := users.UpsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := ParseNotificationTemplates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat := &fakeNotifier{}
+	clock := &fakeClock{now: time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)}
+	svc := NewNotificationService(memory.NewNotificationRepo(db), users,
+		map[domain.NotificationChannel]domain.Notifier{domain.NotifyChat: chat},
+		templates, clock, NotificationConfig{BatchSize: 10, Lease: time.Minute, MaxAttempts: 3},
+	)
+
+	overdue := domain.OverdueReview{
+		PullRequestID: "pr-1", PullRequestName: "Fix login", AuthorID: "u1", TeamName: "backend",
+		ReviewerID: "u2", AssignedAt: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC),
+		SLA: domain.ReviewSLA{Hours: 24, Policy: domain.SLAEscalate, LeadID: "lead"},
+	}
+	e := domain.NewReviewOverdueEvent(overdue, "lead", clock.now)
+	e.ID = 1
+	if err := svc.Publish(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SendOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	subjects := map[string]string{}
+	for _, n := range chat.sent {
+		subjects[n.To] = n.Subject
+	}
+	if subjects["@u2"] != "Ревью pr-1 просрочено" || subjects["@lead"] != "Просрочено ревью pr-1" || len(chat.sent) != 2 {
+		t.Errorf("sent = %+v", chat.sent)
+	}
+}
+
+func TestParseNotificationTemplates(t *testing.T) {
+	templates, err := ParseNotificationTemplates(map[domain.EventType]string{
+		domain.EventPRMerged: "Merged {{.Payload.pull_request_id}}\nby {{.Payload.author_id}} for {{.Recipient.Username}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := domain.NewPRMergedEvent(domain.PullRequest{ID: "pr-1", AuthorID: "u1"}, time.Now())
+	subject, body, err := templates.render(NotificationData{
+		Recipient: domain.User{ID: "u2", Username: "bob"},
+		Event:     e,
+		Payload:   map[string]any{"pull_request_id": "pr-1", "author_id": "u1"},
+	})
+	if err != nil || subject != "Merged pr-1" || body != "by u1 for bob" {
+		t.Errorf("render = %q, %q, %v", subject, body, err)
+	}
+
+	if _, err := ParseNotificationTemplates(map[domain.EventType]string{"Nope": "x"}); err == nil {
+		t.Error("unknown event type accepted")
+	}
+	if _, err := ParseNotificationTemplates(map[domain.EventType]string{domain.EventPRMerged: "{{"}); err == nil {
+		t.Error("broken template accepted")
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"prservice/internal/domain"
+)
+
+// NotificationData — данные шаблона уведомления
+type NotificationData struct {
+	Recipient domain.User
+	// Role — кем получатель приходится PR: assigned (назначен ревьювером), unassigned (снят),
+	// author (автор), reviewer (ревьювер влитого PR), overdue (просрочил ревью), lead (лид, которому
+	// эскалирована просрочка)
+	Role  string
+	Event domain.Event
+	// Payload — полезная нагрузка события с полями, как в JSON события
+	Payload map[string]any
+}
+
+// NotificationTemplates — шаблоны уведомлений по типам событий (text/template).
+// Первая строка результата — тема письма, остальное — текст.
+type NotificationTemplates map[domain.EventType]*template.Template
+
+var defaultNotificationTemplates = map[domain.EventType]string{
+	domain.EventReviewerAssigned: `Вас назначили ревьювером {{.Payload.pull_request_id}}
+PR «{{.Payload.pull_request_name}}» от {{.Payload.author_id}} ждёт вашего ревью.`,
+	domain.EventReviewerReassigned: `{{if eq .Role "assigned"}}Вас назначили ревьювером {{.Payload.pull_request_id}}
+PR «{{.Payload.pull_request_name}}» от {{.Payload.author_id}} передан вам на ревью вместо {{.Payload.old_reviewer_id}}.
+{{- else}}Вы больше не ревьювер {{.Payload.pull_request_id}}
+PR «{{.Payload.pull_request_name}}» {{with .Payload.new_reviewer_id}}передан {{.}}{{else}}снят с вас без замены{{end}}.
+{{- end}}`,
+	domain.EventPRMerged: `PR {{.Payload.pull_request_id}} влит
+PR «{{.Payload.pull_request_name}}» от {{.Payload.author_id}} влит{{if eq .Role "reviewer"}}, ваше ревью больше не нужно{{end}}.`,
+	domain.EventReviewOverdue: `{{if eq .Role "lead"}}Просрочено ревью {{.Payload.pull_request_id}}
+{{.Payload.reviewer_id}} не отреагировал на PR «{{.Payload.pull_request_name}}» от {{.Payload.author_id}} до {{.Payload.due_at}}.
+{{- else}}Ревью {{.Payload.pull_request_id}} просрочено
+PR «{{.Payload.pull_request_name}}» от {{.Payload.author_id}} ждёт вашего ревью с {{.Payload.assigned_at}}, срок истёк {{.Payload.due_at}}.
+{{- end}}`,
+}
+
+// ParseNotificationTemplates — шаблоны по умолчанию, в которых заменены шаблоны из overrides
+func ParseNotificationTemplates(overrides map[domain.EventType]string) (NotificationTemplates, error) {
+	res := make(NotificationTemplates, len(defaultNotificationTemplates))
+	for t, text := range defaultNotificationTemplates {
+		if o, ok := overrides[t]; ok {
+			text = o
+		}
+		tmpl, err := template.New(string(t)).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("notification template %s: %w", t, err)
+		}
+		res[t] = tmpl
+	}
+	for t := range overrides {
+		if _, ok := res[t]; !ok {
+			return nil, fmt.Errorf("notification template %s: unknown event type", t)
+		}
+	}
+	return res, nil
+}
+
+// render — тема и текст уведомления
+func (t NotificationTemplates) render(data NotificationData) (subject, body string, err error) {
+	tmpl, ok := t[data.Event.Type]
+	if !ok {
+		return "", "", fmt.Errorf("no notification template for %s", data.Event.Type)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", "", err
+	}
+	subject, body, _ = strings.Cut(strings.TrimSpace(sb.String()), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body), nil
+}
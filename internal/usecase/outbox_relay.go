@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// OutboxRelayConfig — параметры доставки событий из outbox
+type OutboxRelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease — сколько взятое событие недоступно другим relay, пока идёт попытка доставки
+	Lease time.Duration
+	// MinBackoff, MaxBackoff — границы экспоненциальной задержки между попытками
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// OutboxRelay доставляет события из outbox издателю не менее одного раза: событие
+// отмечается доставленным только после успешной публикации, при ошибке — повтор
+// с экспоненциальной задержкой. Порядок соблюдается внутри пачки, но не между повторами.
+type OutboxRelay struct {
+	outbox    domain.OutboxRepository
+	publisher domain.EventPublisher
+	clock     Clock
+	cfg       OutboxRelayConfig
+}
+
+func NewOutboxRelay(
+	outbox domain.OutboxRepository,
+	publisher domain.EventPublisher,
+	clock Clock,
+	cfg OutboxRelayConfig,
+) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, publisher: publisher, clock: clock, cfg: cfg}
+}
+
+// Run доставляет события, пока не отменён ctx
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	poll(ctx, "outbox relay", r.cfg.PollInterval, r.cfg.BatchSize, r.RelayOnce)
+	return nil
+}
+
+// RelayOnce берёт одну пачку событий и публикует их; возвращает размер пачки.
+// Если ctx отменён посреди пачки, неотмеченные события снова станут доступны после аренды.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	now := r.clock.Now()
+	entries, err := r.outbox.ClaimPending(ctx, now, now.Add(r.cfg.Lease), r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return len(entries), nil
+		}
+
+		if err := r.publisher.Publish(ctx, e.Event); err != nil {
+			if ctx.Err() != nil {
+				return len(entries), nil
+			}
+			next := r.clock.Now().Add(backoff(e.Attempts, r.cfg.MinBackoff, r.cfg.MaxBackoff))
+			if err := r.outbox.MarkFailed(ctx, e.ID, err.Error(), next); err != nil {
+				return len(entries), err
+			}
+			continue
+		}
+
+		if err := r.outbox.MarkDelivered(ctx, e.ID, r.clock.Now()); err != nil {
+			return len(entries), err
+		}
+	}
+	return len(entries), nil
+}
+
+// Publishers публикует событие каждому издателю по очереди. Ошибка любого из них —
+// повторная публикация всем, поэтому издатели должны быть идемпотентны по ID события.
+type Publishers []domain.EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, e domain.Event) error {
+	for _, pub := range p {
+		if err := pub.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
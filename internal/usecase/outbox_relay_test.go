@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+type fakeOutbox struct {
+	pending   []domain.OutboxEntry
+	delivered []int64
+	failed    map[int64]time.Time
+}
+
+func (f *fakeOutbox) ClaimPending(_ context.Context, _, _ time.Time, limit int) ([]domain.OutboxEntry, error) {
+	n := min(limit, len(f.pending))
+	res := f.pending[:n]
+	f.pending = f.pending[n:]
+	for i := range res {
+		res[i].Attempts++
+	}
+	return res, nil
+}
+
+func (f *fakeOutbox) MarkDelivered(_ context.Context, id int64, _ time.Time) error {
+	f.delivered = append(f.delivered, id)
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(_ context.Context, id int64, _ string, next time.Time) error {
+	f.failed[id] = next
+	return nil
+}
+
+// fakePublisher отказывает в публикации событий из fail
+type fakePublisher struct {
+	fail      map[int64]bool
+	published []int64
+}
+
+func (f *fakePublisher) Publish(_ context.Context, e domain.Event) error {
+	if f.fail[e.ID] {
+		return errors.New("unavailable")
+	}
+	f.published = append(f.published, e.ID)
+	return nil
+}
+
+func TestOutboxRelayOnce(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	outbox := &fakeOutbox{
+		pending: []domain.OutboxEntry{
+			{Event: domain.Event{ID: 1}},
+			{Event: domain.Event{ID: 2}, Attempts: 2},
+			{Event: domain.Event{ID: 3}},
+		},
+		failed: make(map[int64]time.Time),
+	}
+	publisher := &fakePublisher{fail: map[int64]bool{2: true}}
+	relay := NewOutboxRelay(outbox, publisher, fakeClock{now: now}, OutboxRelayConfig{
+		BatchSize:  10,
+		Lease:      time.Minute,
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+	})
+
+	n, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("got batch of %d, want 3", n)
+	}
+	if len(outbox.delivered) != 2 || outbox.delivered[0] != 1 || outbox.delivered[1] != 3 {
+		t.Fatalf("got delivered %v, want [1 3]", outbox.delivered)
+	}
+	// третья попытка: 1s * 2 * 2
+	if got, want := outbox.failed[2], now.Add(4*time.Second); !got.Equal(want) {
+		t.Fatalf("got next attempt %v, want %v", got, want)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 5, want: 16 * time.Second},
+		{attempt: 7, want: 30 * time.Second},
+		{attempt: 100, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempt, time.Second, 30*time.Second); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// poll вызывает once каждые interval, пока не отменён ctx. Если once обработал полную пачку
+// (batch элементов), следующая берётся сразу, без ожидания.
+func poll(ctx context.Context, name string, interval time.Duration, batch int, once func(ctx context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := once(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("%s: %v", name, err)
+		}
+		if err == nil && n == batch && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backoff — задержка перед следующей попыткой после attempt-й неудачной (с 1):
+// min, 2*min, 4*min, ... но не больше max
+func backoff(attempt int, min, max time.Duration) time.Duration {
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
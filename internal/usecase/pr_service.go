@@ -2,23 +2,54 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"prservice/internal/domain"
 )
 
 type PRService struct {
-	prs   domain.PRRepository
-	users domain.UserRepository
+	prs       domain.PRRepository
+	users     domain.UserRepository
+	teams     domain.TeamRepository
+	selectors domain.ReviewerSelectorProvider
+	// seeds — источник зёрен для решений по назначению
+	seeds *rand.Rand
+	clock Clock
 }
 
-func NewPRService(prs domain.PRRepository, users domain.UserRepository) *PRService {
-	return &PRService{prs: prs, users: users}
+// NewPRService создаёт сервис PR. rnd — источник зёрен для решений по назначению
+// (зерно каждого решения сохраняется в трассировке), clock — источник времени.
+func NewPRService(
+	prs domain.PRRepository,
+	users domain.UserRepository,
+	teams domain.TeamRepository,
+	selectors domain.ReviewerSelectorProvider,
+	rnd rand.Source,
+	clock Clock,
+) *PRService {
+	return &PRService{
+		prs:       prs,
+		users:     users,
+		teams:     teams,
+		selectors: selectors,
+		seeds:     rand.New(&lockedSource{src: rnd}),
+		clock:     clock,
+	}
 }
 
-// Создание PR + автоназначение ревьюверов
-func (s *PRService) CreatePR(ctx context.Context, pr domain.PullRequest) (*domain.PullRequest, error) {
+// Создание PR + автоназначение ревьюверов из целевой команды PR
+// (если не задана — основная команда автора).
+// PR со статусом DRAFT создаётся без ревьюверов — они назначаются в MarkReady.
+// seed (необязательный) задаёт зерно решения, например чтобы повторить решение из трассировки.
+func (s *PRService) CreatePR(
+	ctx context.Context,
+	pr domain.PullRequest,
+	seed *int64,
+) (*domain.PullRequest, error) {
 	existing, err := s.prs.GetByID(ctx, pr.ID)
 	if err == nil && existing != nil {
 		return nil, domain.ErrPRExists
@@ -29,46 +60,150 @@ func (s *PRService) CreatePR(ctx context.Context, pr domain.PullRequest) (*domai
 		return nil, domain.ErrNotFound
 	}
 
-	now := time.Now().UTC()
-	pr.Status = domain.PRStatusOpen
+	if pr.TeamName == "" {
+		pr.TeamName = author.TeamName
+	} else {
+		team, err := s.teams.GetTeam(ctx, pr.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil || team.ArchivedAt != nil {
+			return nil, domain.ErrNotFound
+		}
+	}
+
+	now := s.clock.Now()
+	if pr.Status != domain.PRStatusDraft {
+		pr.Status = domain.PRStatusOpen
+	}
+	pr.AssignedReviewers = nil
+	pr.ChangedFiles = normalizePaths(pr.ChangedFiles)
 	pr.CreatedAt = &now
 
 	var result *domain.PullRequest
 
 	err = s.prs.WithTx(ctx, func(tx domain.PRTx) error {
-		if err := tx.Create(ctx, pr); err != nil {
+		if err := tx.Create(ctx, pr, now); err != nil {
 			return err
 		}
 
-		candidates, err := s.users.ListActiveByTeamExcept(ctx, author.TeamName, []domain.UserID{author.ID})
+		if pr.Status == domain.PRStatusOpen {
+			if err := s.assignReviewers(ctx, tx, &pr, seed); err != nil {
+				return err
+			}
+			if err := tx.Update(ctx, pr, now); err != nil {
+				return err
+			}
+		}
+
+		events := append([]domain.Event{domain.NewPRCreatedEvent(pr, now)}, reviewerAssignedEvents(&pr, now)...)
+		if err := tx.SaveEvents(ctx, events); err != nil {
+			return err
+		}
+
+		loaded, err := tx.GetByIDForUpdate(ctx, pr.ID)
 		if err != nil {
 			return err
 		}
+		loaded.ChangedFiles = pr.ChangedFiles
+		loaded.Assignments = pr.Assignments
+		result = loaded
+		return nil
+	})
 
-		// перемешиваем
-		rand.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
-		maxRev := 2
-		if len(candidates) < maxRev {
-			maxRev = len(candidates)
+// MarkReady переводит DRAFT в OPEN и назначает ревьюверов
+func (s *PRService) MarkReady(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	return s.openWithReviewers(ctx, id, domain.PRStatusDraft)
+}
+
+// Reopen переводит CLOSED в OPEN и заново назначает ревьюверов
+func (s *PRService) Reopen(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	return s.openWithReviewers(ctx, id, domain.PRStatusClosed)
+}
+
+// Close закрывает PR без merge и освобождает ревьюверов
+func (s *PRService) Close(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	var result *domain.PullRequest
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		pr, err := tx.GetByIDForUpdate(ctx, id)
+		if err != nil || pr == nil {
+			return domain.ErrNotFound
 		}
 
-		reviewers := make([]domain.UserID, maxRev)
-		for i := 0; i < maxRev; i++ {
-			reviewers[i] = candidates[i].ID
+		now := s.clock.Now()
+		if err := pr.TransitionTo(domain.PRStatusClosed, now); err != nil {
+			return err
 		}
-		pr.AssignedReviewers = reviewers
+		if err := tx.Update(ctx, *pr, now); err != nil {
+			return err
+		}
+		result = pr
+		return nil
+	})
 
-		if err := tx.Update(ctx, pr); err != nil {
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// openWithReviewers — переход from -> OPEN с назначением ревьюверов
+func (s *PRService) openWithReviewers(
+	ctx context.Context,
+	id domain.PullRequestID,
+	from domain.PRStatus,
+) (*domain.PullRequest, error) {
+	var result *domain.PullRequest
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		pr, err := tx.GetByIDForUpdate(ctx, id)
+		if err != nil || pr == nil {
+			return domain.ErrNotFound
+		}
+		if pr.Status != from {
+			return domain.ErrInvalidTransition
+		}
+
+		// команда PR могла быть удалена — берём основную команду автора
+		if pr.TeamName == "" {
+			author, err := s.users.GetByID(ctx, pr.AuthorID)
+			if err != nil || author == nil {
+				return domain.ErrNotFound
+			}
+			pr.TeamName = author.TeamName
+		}
+
+		if pr.ChangedFiles, err = tx.ListChangedFiles(ctx, id); err != nil {
 			return err
 		}
 
-		loaded, err := tx.GetByIDForUpdate(ctx, pr.ID)
+		now := s.clock.Now()
+		if err := pr.TransitionTo(domain.PRStatusOpen, now); err != nil {
+			return err
+		}
+		if err := s.assignReviewers(ctx, tx, pr, nil); err != nil {
+			return err
+		}
+		if err := tx.Update(ctx, *pr, now); err != nil {
+			return err
+		}
+		if err := tx.SaveEvents(ctx, reviewerAssignedEvents(pr, now)); err != nil {
+			return err
+		}
+
+		loaded, err := tx.GetByIDForUpdate(ctx, id)
 		if err != nil {
 			return err
 		}
+		loaded.ChangedFiles = pr.ChangedFiles
+		loaded.Assignments = pr.Assignments
 		result = loaded
 		return nil
 	})
@@ -79,8 +214,9 @@ func (s *PRService) CreatePR(ctx context.Context, pr domain.PullRequest) (*domai
 	return result, nil
 }
 
-// Merge (идемпотентный)
-func (s *PRService) Merge(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+// Merge (идемпотентный).
+// Без force требует, чтобы число APPROVED достигло required_approvals команды PR.
+func (s *PRService) Merge(ctx context.Context, id domain.PullRequestID, force bool) (*domain.PullRequest, error) {
 	var result *domain.PullRequest
 
 	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
@@ -93,12 +229,29 @@ func (s *PRService) Merge(ctx context.Context, id domain.PullRequestID) (*domain
 			result = pr
 			return nil
 		}
+		if !pr.Status.CanTransitionTo(domain.PRStatusMerged) {
+			return domain.ErrInvalidTransition
+		}
 
-		now := time.Now().UTC()
-		pr.Status = domain.PRStatusMerged
-		pr.MergedAt = &now
+		if !force {
+			settings, err := s.teamSettings(ctx, pr.TeamName)
+			if err != nil {
+				return err
+			}
+			if pr.Approvals() < settings.RequiredApprovals {
+				return domain.ErrApprovalQuorumNotMet
+			}
+		}
 
-		if err := tx.Update(ctx, *pr); err != nil {
+		now := s.clock.Now()
+		if err := pr.TransitionTo(domain.PRStatusMerged, now); err != nil {
+			return err
+		}
+
+		if err := tx.Update(ctx, *pr, now); err != nil {
+			return err
+		}
+		if err := tx.SaveEvents(ctx, []domain.Event{domain.NewPRMergedEvent(*pr, now)}); err != nil {
 			return err
 		}
 		result = pr
@@ -111,11 +264,56 @@ func (s *PRService) Merge(ctx context.Context, id domain.PullRequestID) (*domain
 	return result, nil
 }
 
-// Переназначение ревьювера
+// SubmitReview — ревьювер выставляет состояние ревью (APPROVED / CHANGES_REQUESTED / COMMENTED)
+func (s *PRService) SubmitReview(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	state domain.ReviewState,
+) (*domain.PullRequest, error) {
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result *domain.PullRequest
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		pr, err := tx.GetByIDForUpdate(ctx, prID)
+		if err != nil || pr == nil {
+			return domain.ErrNotFound
+		}
+		if err := pr.ErrIfNotOpen(); err != nil {
+			return err
+		}
+		if reviewerIndex(pr, reviewerID) == -1 {
+			return domain.ErrNotAssigned
+		}
+
+		if err := tx.SetReviewState(ctx, prID, reviewerID, state, s.clock.Now()); err != nil {
+			return err
+		}
+
+		loaded, err := tx.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		result = loaded
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Переназначение ревьювера на другого участника команды PR.
+// seed (необязательный) задаёт зерно решения.
 func (s *PRService) ReassignReviewer(
 	ctx context.Context,
 	prID domain.PullRequestID,
 	oldReviewer domain.UserID,
+	seed *int64,
 ) (*domain.PullRequest, domain.UserID, error) {
 	var result *domain.PullRequest
 	var newReviewerID domain.UserID
@@ -125,47 +323,56 @@ func (s *PRService) ReassignReviewer(
 		if err != nil || pr == nil {
 			return domain.ErrNotFound
 		}
-		if pr.Status == domain.PRStatusMerged {
-			return domain.ErrPRMerged
+		if err := pr.ErrIfNotOpen(); err != nil {
+			return err
 		}
 
-		idx := -1
-		for i, r := range pr.AssignedReviewers {
-			if r == oldReviewer {
-				idx = i
-				break
-			}
-		}
+		idx := reviewerIndex(pr, oldReviewer)
 		if idx == -1 {
 			return domain.ErrNotAssigned
 		}
 
-		oldUser, err := s.users.GetByID(ctx, oldReviewer)
-		if err != nil || oldUser == nil {
-			return domain.ErrNotFound
+		// замена один на один не увеличивает число ревьюверов, поэтому разрешена
+		// и после уменьшения max_reviewers команды
+		pools := s.newReviewerPools(nil)
+		owned, err := s.codeOwnedPaths(ctx, tx, pools, pr)
+		if err != nil {
+			return err
 		}
 
-		exclude := append([]domain.UserID{oldReviewer}, pr.AssignedReviewers...)
-		candidates, err := s.users.ListActiveByTeamExcept(ctx, oldUser.TeamName, exclude)
+		d := s.newDecision(domain.TraceReassign, pr, seed)
+		d.trace.ReplacedReviewer = oldReviewer
+		picked, atCapacity, err := s.pickReplacement(ctx, pools, d, pr, owned, oldReviewer)
 		if err != nil {
 			return err
 		}
-		if len(candidates) == 0 {
+		if len(picked) == 0 {
+			if atCapacity > 0 {
+				return domain.ErrAllReviewersAtCapacity
+			}
 			return domain.ErrNoCandidate
 		}
+		newID := picked[0].ReviewerID
 
-		rand.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
-		newUser := candidates[0]
-
-		pr.AssignedReviewers[idx] = newUser.ID
+		pr.AssignedReviewers[idx] = newID
 
-		if err := tx.Update(ctx, *pr); err != nil {
+		now := s.clock.Now()
+		if err := tx.Update(ctx, *pr, now); err != nil {
 			return err
 		}
-		result = pr
-		newReviewerID = newUser.ID
+		if err := tx.SaveAssignmentTrace(ctx, d.finish(picked)); err != nil {
+			return err
+		}
+		event := domain.NewReviewerReassignedEvent(*pr, oldReviewer, newID, now)
+		if err := tx.SaveEvents(ctx, []domain.Event{event}); err != nil {
+			return err
+		}
+		loaded, err := tx.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		result = loaded
+		newReviewerID = newID
 		return nil
 	})
 
@@ -175,6 +382,387 @@ func (s *PRService) ReassignReviewer(
 	return result, newReviewerID, nil
 }
 
+// Размер страницы /pullRequest/list
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+func (s *PRService) GetPR(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	pr, err := s.prs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, domain.ErrNotFound
+	}
+	return pr, nil
+}
+
+// ListPRs — страница PR по фильтру. cursor — значение next_cursor предыдущей страницы,
+// пустой next_cursor в ответе означает последнюю страницу.
+func (s *PRService) ListPRs(
+	ctx context.Context,
+	filter domain.PRFilter,
+	cursor string,
+) ([]domain.PullRequest, string, error) {
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultPageSize
+	case filter.Limit < 0 || filter.Limit > MaxPageSize:
+		return nil, "", domain.ErrInvalidFilter
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, "", domain.ErrInvalidFilter
+	}
+
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.After = after
+	}
+
+	// запрашиваем на один больше, чтобы понять, есть ли следующая страница
+	pageSize := filter.Limit
+	filter.Limit++
+	prs, err := s.prs.List(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(prs) <= pageSize {
+		return prs, "", nil
+	}
+
+	prs = prs[:pageSize]
+	last := prs[len(prs)-1]
+	next := domain.PRCursor{ID: last.ID}
+	if last.CreatedAt != nil {
+		next.CreatedAt = *last.CreatedAt
+	}
+	return prs, encodeCursor(next), nil
+}
+
+// GetAssignmentTraces — все решения по назначению ревьюверов PR, от старых к новым
+func (s *PRService) GetAssignmentTraces(
+	ctx context.Context,
+	id domain.PullRequestID,
+) ([]domain.AssignmentTrace, error) {
+	if _, err := s.GetPR(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.prs.ListAssignmentTraces(ctx, id)
+}
+
 func (s *PRService) ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]domain.PullRequest, error) {
 	return s.prs.ListByReviewer(ctx, reviewerID)
 }
+
+// assignReviewers подбирает ревьюверов на PR: сначала по владельцу на каждый изменённый путь
+// (правила владения кодом команды PR), затем оставшиеся места до max_reviewers — стратегией
+// из участников команды PR, при нехватке — из её запасных команд.
+// Владельцев может оказаться больше max_reviewers: покрытие путей важнее лимита.
+// Решение записывается в трассировку назначения.
+func (s *PRService) assignReviewers(
+	ctx context.Context,
+	tx domain.PRTx,
+	pr *domain.PullRequest,
+	seed *int64,
+) error {
+	settings, err := s.teamSettings(ctx, pr.TeamName)
+	if err != nil {
+		return err
+	}
+
+	pools := s.newReviewerPools(nil)
+	d := s.newDecision(domain.TraceAssign, pr, seed)
+	exclude := []domain.UserID{pr.AuthorID}
+
+	owners, err := s.pickCodeOwners(ctx, pools, d, pr.TeamName, pr.ChangedFiles, exclude)
+	if err != nil {
+		return err
+	}
+	for _, a := range owners {
+		exclude = append(exclude, a.ReviewerID)
+	}
+
+	rest, atCapacity, err := s.pickReviewers(ctx, pools, d, pr.TeamName, exclude, settings.MaxReviewers-len(owners))
+	if err != nil {
+		return err
+	}
+	picked := append(owners, rest...)
+
+	if len(picked) == 0 && atCapacity > 0 {
+		return domain.ErrAllReviewersAtCapacity
+	}
+	if len(picked) < settings.MinReviewers {
+		return domain.ErrNotEnoughReviewers
+	}
+
+	reviewers := make([]domain.UserID, len(picked))
+	for i, a := range picked {
+		reviewers[i] = a.ReviewerID
+	}
+	pr.AssignedReviewers = reviewers
+	pr.Assignments = picked
+	return tx.SaveAssignmentTrace(ctx, d.finish(picked))
+}
+
+// codeOwnedPaths — изменённые пути PR (из хранилища), которыми по правилам владения кодом
+// команды PR владеет каждый из владельцев этих путей
+func (s *PRService) codeOwnedPaths(
+	ctx context.Context,
+	tx domain.PRTx,
+	pools *reviewerPools,
+	pr *domain.PullRequest,
+) (map[domain.UserID][]string, error) {
+	matcher, err := pools.matcher(ctx, pr.TeamName)
+	if err != nil || matcher == nil {
+		return nil, err
+	}
+	files, err := tx.ListChangedFiles(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[domain.UserID][]string)
+	for _, f := range files {
+		for _, id := range matcher.OwnersFor(f) {
+			res[id] = append(res[id], f)
+		}
+	}
+	return res, nil
+}
+
+// uncoveredPaths — пути ревьювера reviewer, которыми не владеет никто из остальных ревьюверов PR
+func uncoveredPaths(owned map[domain.UserID][]string, reviewers []domain.UserID, reviewer domain.UserID) []string {
+	var res []string
+	for _, f := range owned[reviewer] {
+		covered := slices.ContainsFunc(reviewers, func(id domain.UserID) bool {
+			return id != reviewer && slices.Contains(owned[id], f)
+		})
+		if !covered {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// pickReplacement выбирает замену ревьюверу old: пути, которые после его снятия остались бы
+// без владельца, передаются другому их владельцу; если таких путей нет или владельца не нашлось —
+// замена выбирается стратегией. Второе значение — как у pickReviewers.
+func (s *PRService) pickReplacement(
+	ctx context.Context,
+	pools *reviewerPools,
+	d *decision,
+	pr *domain.PullRequest,
+	owned map[domain.UserID][]string,
+	old domain.UserID,
+) ([]domain.ReviewerAssignment, int, error) {
+	exclude := append([]domain.UserID{pr.AuthorID}, pr.AssignedReviewers...)
+	if uncovered := uncoveredPaths(owned, pr.AssignedReviewers, old); len(uncovered) > 0 {
+		owners, err := s.pickCodeOwners(ctx, pools, d, pr.TeamName, uncovered, exclude)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(owners) > 0 {
+			return owners[:1], 0, nil
+		}
+	}
+	return s.pickReviewers(ctx, pools, d, pr.TeamName, exclude, 1)
+}
+
+// newDecision начинает решение по назначению с заданным зерном или, если оно не задано, — со случайным
+func (s *PRService) newDecision(
+	kind domain.AssignmentTraceKind,
+	pr *domain.PullRequest,
+	seed *int64,
+) *decision {
+	if seed == nil {
+		v := s.seeds.Int63()
+		seed = &v
+	}
+	return newDecision(kind, pr, *seed, s.clock.Now())
+}
+
+// ReassignOpenReviews снимает пользователей с их OPEN PR (только PR команды team, если она
+// задана) и подбирает замену из команды каждого PR, при нехватке — из её запасных команд.
+// Пути, которые остались бы без ревьювера-владельца, передаются другому их владельцу.
+// Вызывается внутри уже открытой транзакции, пользователи к этому моменту должны быть
+// деактивированы или исключены из команды.
+// PR и ревьюверы читаются одним запросом, пул кандидатов — один раз на команду.
+// Если замены нет, ревьювер просто снимается с PR.
+func (s *PRService) ReassignOpenReviews(
+	ctx context.Context,
+	tx domain.PRTx,
+	users []domain.User,
+	team domain.TeamName,
+) (*domain.ReassignmentReport, error) {
+	report := &domain.ReassignmentReport{}
+	if len(users) == 0 {
+		return report, nil
+	}
+
+	removed := make(map[domain.UserID]domain.User, len(users))
+	ids := make([]domain.UserID, 0, len(users))
+	for _, u := range users {
+		removed[u.ID] = u
+		ids = append(ids, u.ID)
+	}
+
+	prs, err := tx.ListOpenByReviewersForUpdate(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// пулы кандидатов по командам, без снимаемых пользователей
+	pools := s.newReviewerPools(removed)
+
+	var (
+		changes []domain.ReviewReassignment
+		traces  []domain.AssignmentTrace
+		events  []domain.Event
+	)
+	now := s.clock.Now()
+	for i := range prs {
+		pr := &prs[i]
+		if team != "" && pr.TeamName != team {
+			continue
+		}
+		owned, err := s.codeOwnedPaths(ctx, tx, pools, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := 0; idx < len(pr.AssignedReviewers); idx++ {
+			old, ok := removed[pr.AssignedReviewers[idx]]
+			if !ok {
+				continue
+			}
+
+			d := s.newDecision(domain.TraceReassign, pr, nil)
+			d.trace.ReplacedReviewer = old.ID
+			picked, _, err := s.pickReplacement(ctx, pools, d, pr, owned, old.ID)
+			if err != nil {
+				return nil, err
+			}
+			traces = append(traces, d.finish(picked))
+
+			change := domain.ReviewReassignment{PullRequestID: pr.ID, OldReviewerID: old.ID}
+			if len(picked) == 0 {
+				pr.AssignedReviewers = append(pr.AssignedReviewers[:idx], pr.AssignedReviewers[idx+1:]...)
+				idx--
+				report.NoCandidate = append(report.NoCandidate, change)
+			} else {
+				change.NewReviewerID = picked[0].ReviewerID
+				pr.AssignedReviewers[idx] = change.NewReviewerID
+				pools.addLoad(change.NewReviewerID)
+				report.Reassigned = append(report.Reassigned, change)
+			}
+			changes = append(changes, change)
+			events = append(events, domain.NewReviewerReassignedEvent(*pr, old.ID, change.NewReviewerID, now))
+		}
+	}
+
+	if len(changes) == 0 {
+		return report, nil
+	}
+	if err := tx.ReplaceReviewers(ctx, changes, now); err != nil {
+		return nil, err
+	}
+	if err := tx.SaveAssignmentTraces(ctx, traces); err != nil {
+		return nil, err
+	}
+	if err := tx.SaveEvents(ctx, events); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// reviewerAssignedEvents — ReviewerAssigned на каждого назначенного на PR ревьювера
+func reviewerAssignedEvents(pr *domain.PullRequest, at time.Time) []domain.Event {
+	events := make([]domain.Event, 0, len(pr.Assignments))
+	for _, a := range pr.Assignments {
+		events = append(events, domain.NewReviewerAssignedEvent(*pr, a, at))
+	}
+	return events
+}
+
+// teamSettings — настройки команды или значения по умолчанию, если команды нет
+func (s *PRService) teamSettings(ctx context.Context, team domain.TeamName) (domain.TeamSettings, error) {
+	settings, err := s.teams.GetSettings(ctx, team)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if settings == nil {
+		return domain.DefaultTeamSettings(), nil
+	}
+	return *settings, nil
+}
+
+// normalizePaths — непустые изменённые пути без повторов, в исходном порядке
+func normalizePaths(paths []string) []string {
+	seen := make(map[string]struct{}, len(paths))
+	res := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = domain.NormalizePath(p)
+		if _, ok := seen[p]; ok || p == "" {
+			continue
+		}
+		seen[p] = struct{}{}
+		res = append(res, p)
+	}
+	return res
+}
+
+func reviewerIndex(pr *domain.PullRequest, id domain.UserID) int {
+	for i, r := range pr.AssignedReviewers {
+		if r == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func withoutUsers(pool []domain.Candidate, skip map[domain.UserID]domain.User) []domain.Candidate {
+	res := make([]domain.Candidate, 0, len(pool))
+	for _, c := range pool {
+		if _, ok := skip[c.User.ID]; ok {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// addLoad учитывает только что назначенное ревью в пуле кандидатов
+func addLoad(pool []domain.Candidate, id domain.UserID) {
+	for i := range pool {
+		if pool[i].User.ID == id {
+			pool[i].OpenReviews++
+			return
+		}
+	}
+}
+
+func encodeCursor(c domain.PRCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + string(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*domain.PRCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidFilter
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, domain.ErrInvalidFilter
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, domain.ErrInvalidFilter
+	}
+	return &domain.PRCursor{CreatedAt: createdAt, ID: domain.PullRequestID(id)}, nil
+}
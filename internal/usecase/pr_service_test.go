@@ -0,0 +1,645 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"prservice/internal/adapter/repo/memory"
+	"prservice/internal/domain"
+	"prservice/internal/usecase/selector"
+)
+
+// Заглушки портов: реализованы только методы, которые нужны назначению ревьюверов.
+// Вызов любого другого метода паникует на nil-интерфейсе.
+
+type fakeClock struct{ now time.Time }
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+type fakeUsers struct {
+	domain.UserRepository
+	users []domain.User
+}
+
+func (f *fakeUsers) GetByID(_ context.Context, id domain.UserID) (*domain.User, error) {
+	for _, u := range f.users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUsers) ListCandidatesWithLoad(_ context.Context, team domain.TeamName, _ time.Time) ([]domain.Candidate, error) {
+	var res []domain.Candidate
+	for _, u := range f.users {
+		if u.TeamName == team {
+			res = append(res, domain.Candidate{User: u})
+		}
+	}
+	return res, nil
+}
+
+type fakeTeams struct {
+	domain.TeamRepository
+	settings domain.TeamSettings
+	owners   domain.CodeOwners
+}
+
+func (f *fakeTeams) GetSettings(context.Context, domain.TeamName) (*domain.TeamSettings, error) {
+	s := f.settings
+	return &s, nil
+}
+
+func (f *fakeTeams) GetFallbacks(context.Context, domain.TeamName) ([]domain.TeamName, error) {
+	return nil, nil
+}
+
+func (f *fakeTeams) GetCodeOwners(context.Context, domain.TeamName) (*domain.CodeOwners, error) {
+	owners := f.owners
+	return &owners, nil
+}
+
+type fakePRs struct {
+	domain.PRRepository
+	domain.PRTx
+	prs    map[domain.PullRequestID]domain.PullRequest
+	traces []domain.AssignmentTrace
+	events []domain.Event
+}
+
+func newFakePRs(prs ...domain.PullRequest) *fakePRs {
+	f := &fakePRs{prs: make(map[domain.PullRequestID]domain.PullRequest)}
+	for _, pr := range prs {
+		f.prs[pr.ID] = pr
+	}
+	return f
+}
+
+func (f *fakePRs) WithTx(_ context.Context, fn func(tx domain.PRTx) error) error {
+	return fn(f)
+}
+
+func (f *fakePRs) GetByID(_ context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	pr, ok := f.prs[id]
+	if !ok {
+		return nil, nil
+	}
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	return &pr, nil
+}
+
+func (f *fakePRs) GetByIDForUpdate(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error) {
+	return f.GetByID(ctx, id)
+}
+
+func (f *fakePRs) Create(_ context.Context, pr domain.PullRequest, _ time.Time) error {
+	f.prs[pr.ID] = pr
+	return nil
+}
+
+func (f *fakePRs) ListChangedFiles(_ context.Context, id domain.PullRequestID) ([]string, error) {
+	return f.prs[id].ChangedFiles, nil
+}
+
+func (f *fakePRs) Update(_ context.Context, pr domain.PullRequest, _ time.Time) error {
+	f.prs[pr.ID] = pr
+	return nil
+}
+
+func (f *fakePRs) SaveAssignmentTrace(_ context.Context, trace domain.AssignmentTrace) error {
+	f.traces = append(f.traces, trace)
+	return nil
+}
+
+func (f *fakePRs) SaveAssignmentTraces(_ context.Context, traces []domain.AssignmentTrace) error {
+	f.traces = append(f.traces, traces...)
+	return nil
+}
+
+func (f *fakePRs) SaveEvents(_ context.Context, events []domain.Event) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+// eventTypes — типы записанных в outbox событий по порядку
+func (f *fakePRs) eventTypes() []domain.EventType {
+	res := make([]domain.EventType, len(f.events))
+	for i, e := range f.events {
+		res[i] = e.Type
+	}
+	return res
+}
+
+func newTestPRService(t *testing.T, prs *fakePRs, users []domain.User, settings domain.TeamSettings) *PRService {
+	t.Helper()
+	selectors, err := selector.NewProvider(domain.StrategyRandom, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPRService(
+		prs,
+		&fakeUsers{users: users},
+		&fakeTeams{settings: settings},
+		selectors,
+		rand.NewSource(1),
+		fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)},
+	)
+}
+
+func teamUsers(ids ...domain.UserID) []domain.User {
+	res := make([]domain.User, len(ids))
+	for i, id := range ids {
+		res[i] = domain.User{ID: id, Username: string(id), TeamName: "backend", IsActive: true}
+	}
+	return res
+}
+
+func TestCreatePRAssignment(t *testing.T) {
+	maxTwo := domain.TeamSettings{MaxReviewers: 2}
+
+	inactive := teamUsers("u1", "u2", "u3", "u4")
+	inactive[1].IsActive = false
+	inactive[3].IsActive = false
+
+	tests := []struct {
+		name     string
+		users    []domain.User
+		settings domain.TeamSettings
+		// wantCount — сколько ревьюверов должно быть назначено
+		wantCount int
+		// never — кто ни при каком зерне не должен быть назначен
+		never []domain.UserID
+	}{
+		{
+			name:      "author excluded",
+			users:     teamUsers("u1", "u2", "u3"),
+			settings:  maxTwo,
+			wantCount: 2,
+			never:     []domain.UserID{"u1"},
+		},
+		{
+			name:      "author is the only member",
+			users:     teamUsers("u1"),
+			settings:  maxTwo,
+			wantCount: 0,
+			never:     []domain.UserID{"u1"},
+		},
+		{
+			name:      "inactive excluded",
+			users:     inactive,
+			settings:  maxTwo,
+			wantCount: 1,
+			never:     []domain.UserID{"u1", "u2", "u4"},
+		},
+		{
+			name:      "at most max_reviewers",
+			users:     teamUsers("u1", "u2", "u3", "u4", "u5", "u6"),
+			settings:  maxTwo,
+			wantCount: 2,
+			never:     []domain.UserID{"u1"},
+		},
+		{
+			name:      "max_reviewers is one",
+			users:     teamUsers("u1", "u2", "u3", "u4"),
+			settings:  domain.TeamSettings{MaxReviewers: 1},
+			wantCount: 1,
+			never:     []domain.UserID{"u1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				prs := newFakePRs()
+				svc := newTestPRService(t, prs, tt.users, tt.settings)
+
+				pr, err := svc.CreatePR(context.Background(), domain.PullRequest{
+					ID:       "pr-1",
+					Name:     "test",
+					AuthorID: "u1",
+				}, &seed)
+				if err != nil {
+					t.Fatalf("seed %d: CreatePR: %v", seed, err)
+				}
+
+				if len(pr.AssignedReviewers) != tt.wantCount {
+					t.Fatalf("seed %d: got %d reviewers %v, want %d",
+						seed, len(pr.AssignedReviewers), pr.AssignedReviewers, tt.wantCount)
+				}
+				for _, id := range tt.never {
+					if slices.Contains(pr.AssignedReviewers, id) {
+						t.Fatalf("seed %d: %s must not be assigned, got %v", seed, id, pr.AssignedReviewers)
+					}
+				}
+				if len(prs.traces) != 1 || prs.traces[0].Seed != seed {
+					t.Fatalf("seed %d: want one trace with the seed, got %+v", seed, prs.traces)
+				}
+
+				wantEvents := []domain.EventType{domain.EventPRCreated}
+				for range pr.AssignedReviewers {
+					wantEvents = append(wantEvents, domain.EventReviewerAssigned)
+				}
+				if got := prs.eventTypes(); !slices.Equal(got, wantEvents) {
+					t.Fatalf("seed %d: got events %v, want %v", seed, got, wantEvents)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePRSameSeedSameReviewers(t *testing.T) {
+	users := teamUsers("u1", "u2", "u3", "u4", "u5", "u6")
+	settings := domain.TeamSettings{MaxReviewers: 2}
+
+	for seed := int64(0); seed < 20; seed++ {
+		var got [][]domain.UserID
+		for i := 0; i < 2; i++ {
+			svc := newTestPRService(t, newFakePRs(), users, settings)
+			pr, err := svc.CreatePR(context.Background(), domain.PullRequest{
+				ID:       "pr-1",
+				Name:     "test",
+				AuthorID: "u1",
+			}, &seed)
+			if err != nil {
+				t.Fatalf("seed %d: CreatePR: %v", seed, err)
+			}
+			got = append(got, pr.AssignedReviewers)
+		}
+		if !slices.Equal(got[0], got[1]) {
+			t.Fatalf("seed %d: reviewers differ: %v vs %v", seed, got[0], got[1])
+		}
+	}
+}
+
+func TestCreatePRRoundRobinReplay(t *testing.T) {
+	ctx := context.Background()
+	selectors, err := selector.NewProvider(domain.StrategyRoundRobin, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prs := newFakePRs()
+	svc := NewPRService(
+		prs,
+		&fakeUsers{users: teamUsers("u1", "u2", "u3", "u4", "u5")},
+		&fakeTeams{settings: domain.TeamSettings{MaxReviewers: 2}},
+		selectors,
+		rand.NewSource(1),
+		fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)},
+	)
+
+	for _, id := range []domain.PullRequestID{"pr-1", "pr-2", "pr-3"} {
+		if _, err := svc.CreatePR(ctx, domain.PullRequest{ID: id, Name: "test", AuthorID: "u1"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// новая очередь, поставленная на позицию из трассировки, повторяет выбор
+	for i, trace := range prs.traces {
+		team := trace.Teams[0]
+		rr := selector.NewRoundRobin()
+		rr.Restore(team.Team, team.RotationPosition)
+
+		candidates := make([]domain.Candidate, len(team.Candidates))
+		for j, id := range team.Candidates {
+			candidates[j] = domain.Candidate{User: domain.User{ID: id}}
+		}
+		var got []domain.UserID
+		for _, c := range rr.Select(nil, team.Team, candidates, len(trace.Selected)) {
+			got = append(got, c.User.ID)
+		}
+		if want := prs.prs[trace.PullRequestID].AssignedReviewers; !slices.Equal(got, want) {
+			t.Fatalf("trace %d (position %q): replay picked %v, want %v", i, team.RotationPosition, got, want)
+		}
+	}
+	if p := prs.traces[2].Teams[0].RotationPosition; p != "u5" {
+		t.Fatalf("pr-3 rotation position = %q, want u5", p)
+	}
+}
+
+func TestCreatePRUnavailabilityByServiceClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	db := memory.New()
+	teams, users := memory.NewTeamRepo(db), memory.NewUserRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.TeamSettings{MaxReviewers: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range teamUsers("u1", "u2", "u3") {
+		if err := users.UpsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.AddMember(ctx, "backend", u.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// отпуск в момент по часам сервиса, давно закончившийся по настенным часам
+	if _, err := users.AddUnavailability(ctx, domain.Unavailability{
+		UserID:   "u2",
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+		Reason:   domain.UnavailabilityVacation,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	selectors, err := selector.NewProvider(domain.StrategyRandom, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewPRService(memory.NewPRRepo(db), users, teams, selectors, rand.NewSource(1), fakeClock{now: now})
+	pr, err := svc.CreatePR(ctx, domain.PullRequest{ID: "pr-1", Name: "test", AuthorID: "u1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pr.AssignedReviewers, []domain.UserID{"u3"}) {
+		t.Fatalf("reviewers = %v, want [u3]", pr.AssignedReviewers)
+	}
+}
+
+func TestDeactivateOnlyCodeOwnerReviewer(t *testing.T) {
+	ctx := context.Background()
+	owners := domain.CodeOwners{Rules: []domain.CodeOwnerRule{{Pattern: "/api/", Owners: []string{"u2", "u5"}}}}
+	other := map[domain.UserID]domain.UserID{"u2": "u5", "u5": "u2"}
+
+	for seed := int64(0); seed < 20; seed++ {
+		db := memory.New()
+		teams, users, prRepo := memory.NewTeamRepo(db), memory.NewUserRepo(db), memory.NewPRRepo(db)
+		if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.TeamSettings{MaxReviewers: 1}}); err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range teamUsers("u1", "u2", "u3", "u4", "u5", "u6") {
+			if err := users.UpsertUser(ctx, u); err != nil {
+				t.Fatal(err)
+			}
+			if err := teams.AddMember(ctx, "backend", u.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := teams.SetCodeOwners(ctx, "backend", owners); err != nil {
+			t.Fatal(err)
+		}
+
+		selectors, err := selector.NewProvider(domain.StrategyRandom, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock := fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)}
+		prs := NewPRService(prRepo, users, teams, selectors, rand.NewSource(seed), clock)
+		svc := NewUserService(users, teams, prRepo, prs, clock)
+
+		pr, err := prs.CreatePR(ctx, domain.PullRequest{
+			ID:           "pr-1",
+			Name:         "test",
+			AuthorID:     "u1",
+			ChangedFiles: []string{"api/handler.go"},
+		}, &seed)
+		if err != nil {
+			t.Fatalf("seed %d: CreatePR: %v", seed, err)
+		}
+		if len(pr.AssignedReviewers) != 1 || other[pr.AssignedReviewers[0]] == "" {
+			t.Fatalf("seed %d: got reviewers %v, want one owner", seed, pr.AssignedReviewers)
+		}
+
+		owner := pr.AssignedReviewers[0]
+		_, report, err := svc.DeactivateAndReassign(ctx, owner)
+		if err != nil {
+			t.Fatalf("seed %d: DeactivateAndReassign: %v", seed, err)
+		}
+		if len(report.Reassigned) != 1 || report.Reassigned[0].NewReviewerID != other[owner] {
+			t.Fatalf("seed %d: %s replaced as %+v, want the other owner %s", seed, owner, report, other[owner])
+		}
+		traces, err := prRepo.ListAssignmentTraces(ctx, "pr-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sel := traces[len(traces)-1].Selected; len(sel) != 1 || sel[0].Reason != domain.AssignmentCodeOwner {
+			t.Fatalf("seed %d: got selected %+v, want CODE_OWNER", seed, sel)
+		}
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	settings := domain.TeamSettings{MaxReviewers: 2}
+
+	tests := []struct {
+		name      string
+		users     []domain.User
+		reviewers []domain.UserID
+		old       domain.UserID
+		// allowed — допустимые замены; пусто — ожидается wantErr
+		allowed []domain.UserID
+		wantErr error
+	}{
+		{
+			name:      "excludes author and current reviewers",
+			users:     teamUsers("u1", "u2", "u3", "u4", "u5"),
+			reviewers: []domain.UserID{"u2", "u3"},
+			old:       "u2",
+			allowed:   []domain.UserID{"u4", "u5"},
+		},
+		{
+			name:      "single remaining candidate",
+			users:     teamUsers("u1", "u2", "u3", "u4"),
+			reviewers: []domain.UserID{"u2", "u3"},
+			old:       "u3",
+			allowed:   []domain.UserID{"u4"},
+		},
+		{
+			name:      "no candidate besides current reviewers",
+			users:     teamUsers("u1", "u2", "u3"),
+			reviewers: []domain.UserID{"u2", "u3"},
+			old:       "u2",
+			wantErr:   domain.ErrNoCandidate,
+		},
+		{
+			name:      "more reviewers than max_reviewers after it was lowered",
+			users:     teamUsers("u1", "u2", "u3", "u4", "u5"),
+			reviewers: []domain.UserID{"u2", "u3", "u4"},
+			old:       "u2",
+			allowed:   []domain.UserID{"u5"},
+		},
+		{
+			name:      "old reviewer not assigned",
+			users:     teamUsers("u1", "u2", "u3", "u4"),
+			reviewers: []domain.UserID{"u2"},
+			old:       "u3",
+			wantErr:   domain.ErrNotAssigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				prs := newFakePRs(domain.PullRequest{
+					ID:                "pr-1",
+					Name:              "test",
+					AuthorID:          "u1",
+					Status:            domain.PRStatusOpen,
+					TeamName:          "backend",
+					AssignedReviewers: slices.Clone(tt.reviewers),
+				})
+				svc := newTestPRService(t, prs, tt.users, settings)
+
+				pr, newID, err := svc.ReassignReviewer(context.Background(), "pr-1", tt.old, &seed)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("seed %d: got error %v, want %v", seed, err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("seed %d: ReassignReviewer: %v", seed, err)
+				}
+
+				if !slices.Contains(tt.allowed, newID) {
+					t.Fatalf("seed %d: got replacement %s, want one of %v", seed, newID, tt.allowed)
+				}
+				if slices.Contains(pr.AssignedReviewers, tt.old) {
+					t.Fatalf("seed %d: old reviewer still assigned: %v", seed, pr.AssignedReviewers)
+				}
+				if len(pr.AssignedReviewers) != len(tt.reviewers) {
+					t.Fatalf("seed %d: got reviewers %v", seed, pr.AssignedReviewers)
+				}
+				if len(prs.traces) != 1 || prs.traces[0].ReplacedReviewer != tt.old {
+					t.Fatalf("seed %d: want one REASSIGN trace for %s, got %+v", seed, tt.old, prs.traces)
+				}
+				if got := prs.eventTypes(); !slices.Equal(got, []domain.EventType{domain.EventReviewerReassigned}) {
+					t.Fatalf("seed %d: got events %v", seed, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePRCodeOwners(t *testing.T) {
+	owners := domain.CodeOwners{Rules: []domain.CodeOwnerRule{
+		{Pattern: "/api/", Owners: []string{"u2", "u3"}},
+		{Pattern: "/web/", Owners: []string{"u3", "u4"}},
+		{Pattern: "/docs/", Owners: []string{"u5"}},
+		{Pattern: "/db/", Owners: []string{"u1"}},
+	}}
+
+	tests := []struct {
+		name     string
+		files    []string
+		inactive []domain.UserID
+		// want — выбранные владельцы и их пути
+		want map[domain.UserID][]string
+	}{
+		{
+			name:  "owner of several paths first",
+			files: []string{"api/a.go", "web/b.ts", "docs/c.md"},
+			want:  map[domain.UserID][]string{"u3": {"api/a.go", "web/b.ts"}, "u5": {"docs/c.md"}},
+		},
+		{
+			name:     "unavailable owner skipped",
+			files:    []string{"api/a.go", "web/b.ts"},
+			inactive: []domain.UserID{"u3"},
+			want:     map[domain.UserID][]string{"u2": {"api/a.go"}, "u4": {"web/b.ts"}},
+		},
+		{
+			name:  "author is not an owner candidate",
+			files: []string{"db/schema.sql", "docs/c.md"},
+			want:  map[domain.UserID][]string{"u5": {"docs/c.md"}},
+		},
+		{
+			name:  "paths without owners",
+			files: []string{"main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := teamUsers("u1", "u2", "u3", "u4", "u5")
+			for i := range users {
+				if slices.Contains(tt.inactive, users[i].ID) {
+					users[i].IsActive = false
+				}
+			}
+
+			for seed := int64(0); seed < 20; seed++ {
+				// max_reviewers 0: назначаются только владельцы
+				svc := newTestPRService(t, newFakePRs(), users, domain.TeamSettings{})
+				svc.teams.(*fakeTeams).owners = owners
+
+				pr, err := svc.CreatePR(context.Background(), domain.PullRequest{
+					ID:           "pr-1",
+					Name:         "test",
+					AuthorID:     "u1",
+					ChangedFiles: tt.files,
+				}, &seed)
+				if err != nil {
+					t.Fatalf("seed %d: CreatePR: %v", seed, err)
+				}
+
+				got := make(map[domain.UserID][]string)
+				for _, a := range pr.Assignments {
+					if a.Reason != domain.AssignmentCodeOwner {
+						t.Fatalf("seed %d: got assignment %+v", seed, a)
+					}
+					got[a.ReviewerID] = a.Paths
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("seed %d: got owners %v, want %v", seed, got, tt.want)
+				}
+				for id, paths := range tt.want {
+					if !slices.Equal(got[id], paths) {
+						t.Fatalf("seed %d: got owners %v, want %v", seed, got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReassignCodeOwnerOverMaxReviewers(t *testing.T) {
+	// владельцев двое при max_reviewers 1: каждый путь должен остаться покрытым
+	owners := domain.CodeOwners{Rules: []domain.CodeOwnerRule{
+		{Pattern: "/api/", Owners: []string{"u2", "u4"}},
+		{Pattern: "/web/", Owners: []string{"u3", "u5"}},
+	}}
+	sameOwners := map[domain.UserID][]domain.UserID{
+		"u2": {"u4"}, "u4": {"u2"},
+		"u3": {"u5"}, "u5": {"u3"},
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		prs := newFakePRs()
+		svc := newTestPRService(t, prs, teamUsers("u1", "u2", "u3", "u4", "u5", "u6"), domain.TeamSettings{MaxReviewers: 1})
+		svc.teams.(*fakeTeams).owners = owners
+
+		pr, err := svc.CreatePR(context.Background(), domain.PullRequest{
+			ID:           "pr-1",
+			Name:         "test",
+			AuthorID:     "u1",
+			ChangedFiles: []string{"api/handler.go", "web/app.ts"},
+		}, &seed)
+		if err != nil {
+			t.Fatalf("seed %d: CreatePR: %v", seed, err)
+		}
+		if len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("seed %d: got reviewers %v, want one owner per path", seed, pr.AssignedReviewers)
+		}
+
+		old := pr.AssignedReviewers[0]
+		pr, newID, err := svc.ReassignReviewer(context.Background(), "pr-1", old, &seed)
+		if err != nil {
+			t.Fatalf("seed %d: ReassignReviewer: %v", seed, err)
+		}
+		if !slices.Equal(sameOwners[old], []domain.UserID{newID}) {
+			t.Fatalf("seed %d: %s replaced by %s, want owner of the same paths", seed, old, newID)
+		}
+		if len(pr.AssignedReviewers) != 2 || slices.Contains(pr.AssignedReviewers, old) {
+			t.Fatalf("seed %d: got reviewers %v", seed, pr.AssignedReviewers)
+		}
+		if sel := prs.traces[len(prs.traces)-1].Selected; len(sel) != 1 || sel[0].Reason != domain.AssignmentCodeOwner {
+			t.Fatalf("seed %d: got selected %+v, want CODE_OWNER", seed, sel)
+		}
+	}
+}
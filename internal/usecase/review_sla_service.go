@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// ReviewSLAConfig — параметры проверки просроченных ревью
+type ReviewSLAConfig struct {
+	CheckInterval time.Duration
+	BatchSize     int
+}
+
+// ReviewSLAService — отслеживание SLA первого ревью. Run периодически ищет ревью, по которым
+// ревьювер не отреагировал в срок, и поступает с ними по политике команды: напоминает
+// ревьюверу (NOTIFY), дополнительно сообщает лиду (ESCALATE) или переназначает (REASSIGN).
+// Напоминание и эскалация — событие ReviewOverdue, его рассылает NotificationService.
+// Меры по каждому назначению принимаются один раз: просроченное ревью отмечается
+// в той же транзакции, что и событие, и больше не проверяется.
+type ReviewSLAService struct {
+	prs   domain.PRRepository
+	prSvc *PRService
+	clock Clock
+	cfg   ReviewSLAConfig
+}
+
+func NewReviewSLAService(prs domain.PRRepository, prSvc *PRService, clock Clock, cfg ReviewSLAConfig) *ReviewSLAService {
+	return &ReviewSLAService{prs: prs, prSvc: prSvc, clock: clock, cfg: cfg}
+}
+
+// Run проверяет просроченные ревью, пока не отменён ctx
+func (s *ReviewSLAService) Run(ctx context.Context) error {
+	poll(ctx, "review-sla", s.cfg.CheckInterval, s.cfg.BatchSize, s.CheckOnce)
+	return nil
+}
+
+// CheckOnce обрабатывает одну пачку ревью с истёкшим сроком; возвращает, сколько их было
+func (s *ReviewSLAService) CheckOnce(ctx context.Context) (int, error) {
+	list, err := s.prs.ListOverdueCandidates(ctx, s.clock.Now(), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, o := range list {
+		if ctx.Err() != nil {
+			return i, nil
+		}
+		if err := s.handle(ctx, o); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(list), nil
+}
+
+func (s *ReviewSLAService) handle(ctx context.Context, o domain.OverdueReview) error {
+	if o.SLA.Policy == domain.SLAReassign {
+		_, _, err := s.prSvc.ReassignReviewer(ctx, o.PullRequestID, o.ReviewerID, nil)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, domain.ErrNoCandidate), errors.Is(err, domain.ErrAllReviewersAtCapacity):
+			// заменить некем — остаётся напомнить и эскалировать
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrNotAssigned),
+			errors.Is(err, domain.ErrPRNotOpen), errors.Is(err, domain.ErrPRMerged):
+			// PR закрыли или ревьювера сменили после выборки
+			return nil
+		default:
+			return err
+		}
+	}
+
+	var escalatedTo domain.UserID
+	if o.SLA.Policy != domain.SLANotify {
+		escalatedTo = o.SLA.LeadID
+	}
+
+	return s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		now := s.clock.Now()
+		ok, err := tx.MarkReviewOverdue(ctx, o.PullRequestID, o.ReviewerID, o.AssignedAt, now)
+		if err != nil || !ok {
+			return err
+		}
+		return tx.SaveEvents(ctx, []domain.Event{domain.NewReviewOverdueEvent(o, escalatedTo, now)})
+	})
+}
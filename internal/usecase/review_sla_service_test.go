@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"prservice/internal/adapter/repo/memory"
+	"prservice/internal/domain"
+	"prservice/internal/usecase/selector"
+)
+
+func TestReviewSLADueAt(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 10, d, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		hours    int
+		assigned time.Time
+		want     time.Time
+	}{
+		{"weekday", 24, day(1, 10), day(2, 10)},           // среда → четверг
+		{"over weekend", 24, day(3, 15), day(6, 15)},      // пятница → понедельник
+		{"assigned on weekend", 8, day(4, 10), day(6, 8)}, // суббота → понедельник с начала дня
+		{"several days", 48, day(2, 9), day(6, 9)},        // четверг → понедельник
+		{"not UTC", 24, time.Date(2025, 10, 3, 23, 30, 0, 0, time.FixedZone("MSK", 3*3600)), day(6, 20).Add(30 * time.Minute)}, // пятница 20:30 UTC
+	}
+	for _, tt := range tests {
+		sla := domain.ReviewSLA{Hours: tt.hours, Policy: domain.SLANotify}
+		if got := sla.DueAt(tt.assigned); !got.Equal(tt.want) {
+			t.Errorf("%s: DueAt(%v) = %v, want %v", tt.name, tt.assigned, got, tt.want)
+		}
+	}
+}
+
+func TestReviewSLALatestOverdueAssignment(t *testing.T) {
+	start := time.Date(2025, 9, 29, 0, 30, 0, 0, time.UTC) // понедельник
+	for _, hours := range []int{1, 8, 24, 30, 48, 120} {
+		sla := domain.ReviewSLA{Hours: hours, Policy: domain.SLANotify}
+		for assigned := start; assigned.Before(start.AddDate(0, 0, 14)); assigned = assigned.Add(90 * time.Minute) {
+			due := sla.DueAt(assigned)
+			// ровно в срок ревью уже просрочено, минутой раньше — ещё нет
+			if got := sla.LatestOverdueAssignment(due); assigned.After(got) {
+				t.Fatalf("%dh, assigned %v: LatestOverdueAssignment(%v) = %v, want >= assigned", hours, assigned, due, got)
+			}
+			before := due.Add(-time.Minute)
+			if got := sla.LatestOverdueAssignment(before); !assigned.After(got) {
+				t.Fatalf("%dh, assigned %v: LatestOverdueAssignment(%v) = %v, want < assigned", hours, assigned, before, got)
+			}
+		}
+	}
+}
+
+type testReviewSLA struct {
+	svc    *ReviewSLAService
+	teams  *TeamService
+	prs    *PRService
+	outbox *memory.OutboxRepo
+	clock  *fakeClock
+}
+
+// newTestReviewSLA — команда backend из members и пользователь lead вне команды
+func newTestReviewSLA(t *testing.T, members ...domain.UserID) testReviewSLA {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.New()
+	teams, users := memory.NewTeamRepo(db), memory.NewUserRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range teamUsers(members...) {
+		if err := users.UpsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.AddMember(ctx, "backend", u.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := users.UpsertUser(ctx, domain.User{ID: "lead", Username: "lead", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	selectors, err := selector.NewProvider(domain.StrategyRandom, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)}
+	prRepo := memory.NewPRRepo(db)
+	prs := NewPRService(prRepo, users, teams, selectors, rand.NewSource(1), clock)
+	return testReviewSLA{
+		svc:    NewReviewSLAService(prRepo, prs, clock, ReviewSLAConfig{BatchSize: 10}),
+		teams:  NewTeamService(teams, users, prRepo, prs, clock),
+		prs:    prs,
+		outbox: memory.NewOutboxRepo(db),
+		clock:  clock,
+	}
+}
+
+// events — события типа typ из outbox
+func (s testReviewSLA) events(t *testing.T, typ domain.EventType) []domain.Event {
+	t.Helper()
+	entries, err := s.outbox.ClaimPending(context.Background(), s.clock.now, s.clock.now, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []domain.Event
+	for _, e := range entries {
+		if e.Type == typ {
+			res = append(res, e.Event)
+		}
+	}
+	return res
+}
+
+func TestReviewSLAEscalate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewSLA(t, "u1", "u2", "u3")
+
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 24, Policy: domain.SLAEscalate}); !errors.Is(err, domain.ErrInvalidReviewSLA) {
+		t.Errorf("ESCALATE without lead: err = %v", err)
+	}
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 24, Policy: domain.SLAEscalate, LeadID: "nobody"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown lead: err = %v", err)
+	}
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 24, Policy: domain.SLAEscalate, LeadID: "lead"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.prs.CreatePR(ctx, domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := s.prs.SubmitReview(ctx, "pr-1", "u2", domain.ReviewCommented)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rv := range pr.Reviews {
+		if !rv.AssignedAt.Equal(s.clock.now) || (rv.ReviewerID == "u2") != (rv.FirstActedAt != nil) {
+			t.Fatalf("review %+v: want assigned_at from clock and first_acted_at only for u2", rv)
+		}
+	}
+
+	// срок ещё не истёк
+	s.clock.now = s.clock.now.Add(time.Hour)
+	if n, err := s.svc.CheckOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("CheckOnce before due = %d, %v", n, err)
+	}
+
+	// u2 отреагировал, просрочено только ревью u3; меры принимаются один раз
+	s.clock.now = s.clock.now.Add(10 * 24 * time.Hour)
+	for i, want := range []int{1, 0} {
+		if n, err := s.svc.CheckOnce(ctx); err != nil || n != want {
+			t.Fatalf("CheckOnce #%d = %d, %v, want %d", i+1, n, err, want)
+		}
+	}
+
+	events := s.events(t, domain.EventReviewOverdue)
+	if len(events) != 1 {
+		t.Fatalf("ReviewOverdue events = %d, want 1", len(events))
+	}
+	var p domain.ReviewOverduePayload
+	if err := json.Unmarshal(events[0].Payload, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ReviewerID != "u3" || p.EscalatedTo != "lead" || p.Policy != domain.SLAEscalate || !p.DueAt.After(p.AssignedAt) {
+		t.Errorf("payload = %+v", p)
+	}
+
+	pr, err = s.prs.GetPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rv := range pr.Reviews {
+		if (rv.ReviewerID == "u3") != (rv.OverdueAt != nil) {
+			t.Errorf("review %s: overdue_at = %v", rv.ReviewerID, rv.OverdueAt)
+		}
+	}
+}
+
+func TestReviewSLAReassign(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewSLA(t, "u1", "u2", "u3", "u4")
+
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 8, Policy: domain.SLAReassign, LeadID: "lead"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.prs.CreatePR(ctx, domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	s.clock.now = s.clock.now.Add(10 * 24 * time.Hour)
+	if n, err := s.svc.CheckOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("CheckOnce = %d, %v, want 2", n, err)
+	}
+	if got := len(s.events(t, domain.EventReviewerReassigned)); got != 2 {
+		t.Errorf("ReviewerReassigned events = %d, want 2", got)
+	}
+	if got := len(s.events(t, domain.EventReviewOverdue)); got != 0 {
+		t.Errorf("ReviewOverdue events = %d, want 0", got)
+	}
+
+	// новые назначения получают свой срок
+	pr, err := s.prs.GetPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rv := range pr.Reviews {
+		if rv.OverdueAt != nil {
+			t.Errorf("review %s: overdue_at = %v after reassignment", rv.ReviewerID, rv.OverdueAt)
+		}
+	}
+}
+
+func TestReviewSLAReassignWithoutCandidates(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewSLA(t, "u1", "u2", "u3")
+
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 8, Policy: domain.SLAReassign, LeadID: "lead"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.prs.CreatePR(ctx, domain.PullRequest{ID: "pr-1", Name: "Fix login", AuthorID: "u1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// заменить некем — ревьюверам напоминают, лиду сообщают
+	s.clock.now = s.clock.now.Add(10 * 24 * time.Hour)
+	if n, err := s.svc.CheckOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("CheckOnce = %d, %v, want 2", n, err)
+	}
+	if got := len(s.events(t, domain.EventReviewerReassigned)); got != 0 {
+		t.Errorf("ReviewerReassigned events = %d, want 0", got)
+	}
+	overdue := s.events(t, domain.EventReviewOverdue)
+	if len(overdue) != 2 {
+		t.Fatalf("ReviewOverdue events = %d, want 2", len(overdue))
+	}
+	for _, e := range overdue {
+		var p domain.ReviewOverduePayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.EscalatedTo != "lead" || p.Policy != domain.SLAReassign {
+			t.Errorf("payload = %+v", p)
+		}
+	}
+}
+
+func TestReviewSLAShortSLANotStarved(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewSLA(t, "u1", "u2", "u3")
+	s.svc.cfg.BatchSize = 2
+
+	if _, err := s.teams.AddTeam(ctx, domain.Team{
+		Name:     "docs",
+		Settings: domain.DefaultTeamSettings(),
+		Members: []domain.TeamMember{
+			{UserID: "d1", Username: "d1", IsActive: true},
+			{UserID: "d2", Username: "d2", IsActive: true},
+			{UserID: "d3", Username: "d3", IsActive: true},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.teams.SetReviewSLA(ctx, "backend", domain.ReviewSLA{Hours: 24, Policy: domain.SLANotify}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.teams.SetReviewSLA(ctx, "docs", domain.ReviewSLA{Hours: 1, Policy: domain.SLANotify}); err != nil {
+		t.Fatal(err)
+	}
+
+	// пятница: ревью backend назначены раньше, но их срок — только в понедельник
+	s.clock.now = time.Date(2025, 10, 3, 10, 0, 0, 0, time.UTC)
+	for _, id := range []domain.PullRequestID{"pr-1", "pr-2", "pr-3"} {
+		if _, err := s.prs.CreatePR(ctx, domain.PullRequest{ID: id, Name: "Fix", AuthorID: "u1"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.clock.now = s.clock.now.Add(30 * time.Minute)
+	if _, err := s.prs.CreatePR(ctx, domain.PullRequest{ID: "pr-docs", Name: "Docs", AuthorID: "d1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	s.clock.now = time.Date(2025, 10, 4, 12, 0, 0, 0, time.UTC) // суббота
+	if n, err := s.svc.CheckOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("CheckOnce = %d, %v, want 2", n, err)
+	}
+	for _, e := range s.events(t, domain.EventReviewOverdue) {
+		var p domain.ReviewOverduePayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.PullRequestID != "pr-docs" {
+			t.Errorf("overdue review of %s before its due time", p.PullRequestID)
+		}
+	}
+	if n, err := s.svc.CheckOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("second CheckOnce = %d, %v, want 0", n, err)
+	}
+}
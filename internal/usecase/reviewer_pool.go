@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// decision — одно решение по назначению: генератор случайных чисел, которым пользуются
+// стратегии, и трассировка, в которую записываются рассмотренные команды и кандидаты
+type decision struct {
+	rnd    *rand.Rand
+	author domain.UserID
+	trace  domain.AssignmentTrace
+	// teams — индекс команды в trace.Teams
+	teams map[domain.TeamName]int
+}
+
+func newDecision(kind domain.AssignmentTraceKind, pr *domain.PullRequest, seed int64, at time.Time) *decision {
+	return &decision{
+		rnd:    rand.New(rand.NewSource(seed)),
+		author: pr.AuthorID,
+		trace: domain.AssignmentTrace{
+			PullRequestID: pr.ID,
+			Kind:          kind,
+			Seed:          seed,
+			CreatedAt:     at,
+		},
+		teams: make(map[domain.TeamName]int),
+	}
+}
+
+// eligible делит пул команды на кандидатов и отсеянных и записывает это в трассировку.
+// При повторном рассмотрении команды запись заменяется. Второе значение — сколько
+// кандидатов отсеяно из-за лимита открытых ревью.
+func (d *decision) eligible(
+	team domain.TeamName,
+	strategy domain.ReviewerStrategy,
+	pool []domain.Candidate,
+	exclude []domain.UserID,
+) ([]domain.Candidate, int) {
+	candidates, excluded := classify(pool, d.author, exclude)
+
+	t := domain.TeamTrace{Team: team, Strategy: strategy, Excluded: excluded}
+	for _, c := range candidates {
+		t.Candidates = append(t.Candidates, c.User.ID)
+	}
+	if i, ok := d.teams[team]; ok {
+		d.trace.Teams[i] = t
+	} else {
+		d.teams[team] = len(d.trace.Teams)
+		d.trace.Teams = append(d.trace.Teams, t)
+	}
+
+	atCapacity := 0
+	for _, e := range excluded {
+		if e.Reason == domain.ExcludedAtCapacity {
+			atCapacity++
+		}
+	}
+	return candidates, atCapacity
+}
+
+// selectFrom выбирает до n кандидатов стратегией sel. Для стратегий с очередью команды
+// позиция очереди записывается в трассировку команды, уже добавленную через eligible.
+func (d *decision) selectFrom(
+	sel domain.ReviewerSelector,
+	team domain.TeamName,
+	candidates []domain.Candidate,
+	n int,
+) []domain.Candidate {
+	rot, ok := sel.(domain.RotationSelector)
+	if !ok {
+		return sel.Select(d.rnd, team, candidates, n)
+	}
+	res, position := rot.SelectAt(team, candidates, n)
+	d.trace.Teams[d.teams[team]].RotationPosition = position
+	return res
+}
+
+// finish фиксирует выбранных ревьюверов
+func (d *decision) finish(selected []domain.ReviewerAssignment) domain.AssignmentTrace {
+	d.trace.Selected = selected
+	return d.trace
+}
+
+// reviewerPools — пулы кандидатов по командам и запасные команды. Загружаются лениво,
+// один раз на команду, поэтому нагрузка, добавленная через addLoad, видна при следующих выборах.
+type reviewerPools struct {
+	users domain.UserRepository
+	teams domain.TeamRepository
+	// at — момент, на который проверяется недоступность участников
+	at time.Time
+	// skip — пользователи, которых не должно быть ни в одном пуле (например, снимаемые ревьюверы)
+	skip map[domain.UserID]domain.User
+
+	pools     map[domain.TeamName][]domain.Candidate
+	fallbacks map[domain.TeamName][]domain.TeamName
+	// matchers — правила владения кодом команд; nil — правил нет
+	matchers map[domain.TeamName]*domain.CodeOwnersMatcher
+}
+
+func (s *PRService) newReviewerPools(skip map[domain.UserID]domain.User) *reviewerPools {
+	return &reviewerPools{
+		users:     s.users,
+		teams:     s.teams,
+		at:        s.clock.Now(),
+		skip:      skip,
+		pools:     make(map[domain.TeamName][]domain.Candidate),
+		fallbacks: make(map[domain.TeamName][]domain.TeamName),
+		matchers:  make(map[domain.TeamName]*domain.CodeOwnersMatcher),
+	}
+}
+
+func (p *reviewerPools) pool(ctx context.Context, team domain.TeamName) ([]domain.Candidate, error) {
+	if pool, ok := p.pools[team]; ok {
+		return pool, nil
+	}
+	pool, err := p.users.ListCandidatesWithLoad(ctx, team, p.at)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.skip) > 0 {
+		pool = withoutUsers(pool, p.skip)
+	}
+	p.pools[team] = pool
+	return pool, nil
+}
+
+// chain — команда и её запасные команды в порядке приоритета
+func (p *reviewerPools) chain(ctx context.Context, team domain.TeamName) ([]domain.TeamName, error) {
+	fallbacks, ok := p.fallbacks[team]
+	if !ok {
+		var err error
+		if fallbacks, err = p.teams.GetFallbacks(ctx, team); err != nil {
+			return nil, err
+		}
+		p.fallbacks[team] = fallbacks
+	}
+	return append([]domain.TeamName{team}, fallbacks...), nil
+}
+
+// matcher — правила владения кодом команды; nil — правила не заданы
+func (p *reviewerPools) matcher(ctx context.Context, team domain.TeamName) (*domain.CodeOwnersMatcher, error) {
+	if m, ok := p.matchers[team]; ok {
+		return m, nil
+	}
+	rules, err := p.teams.GetCodeOwners(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	var m *domain.CodeOwnersMatcher
+	if rules != nil && len(rules.Rules) > 0 {
+		m = rules.Matcher()
+	}
+	p.matchers[team] = m
+	return m, nil
+}
+
+// addLoad учитывает только что назначенное ревью во всех загруженных пулах
+// (пользователь может состоять в нескольких командах)
+func (p *reviewerPools) addLoad(id domain.UserID) {
+	for _, pool := range p.pools {
+		addLoad(pool, id)
+	}
+}
+
+// pickReviewers выбирает до n ревьюверов для PR команды team: сначала из её участников,
+// затем, если их не хватает, из запасных команд в порядке приоритета. В каждой команде
+// действует её собственная стратегия выбора. Второе значение — сколько кандидатов
+// во всех просмотренных командах отсеяно из-за лимита открытых ревью.
+func (s *PRService) pickReviewers(
+	ctx context.Context,
+	pools *reviewerPools,
+	d *decision,
+	team domain.TeamName,
+	exclude []domain.UserID,
+	n int,
+) ([]domain.ReviewerAssignment, int, error) {
+	chain, err := pools.chain(ctx, team)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	exclude = append([]domain.UserID(nil), exclude...)
+	var (
+		picked     []domain.ReviewerAssignment
+		atCapacity int
+	)
+	for _, t := range chain {
+		if len(picked) >= n {
+			break
+		}
+		pool, err := pools.pool(ctx, t)
+		if err != nil {
+			return nil, 0, err
+		}
+		sel := s.selectors.ForTeam(t)
+		candidates, full := d.eligible(t, sel.Strategy(), pool, exclude)
+		atCapacity += full
+
+		reason := domain.AssignmentStrategy
+		if t != team {
+			reason = domain.AssignmentFallbackTeam
+		}
+		for _, c := range d.selectFrom(sel, t, candidates, n-len(picked)) {
+			picked = append(picked, domain.ReviewerAssignment{ReviewerID: c.User.ID, Reason: reason, Team: t})
+			exclude = append(exclude, c.User.ID)
+		}
+	}
+	return picked, atCapacity, nil
+}
+
+// pickCodeOwners выбирает владельцев изменённых путей по правилам команды PR так, чтобы
+// у каждого пути с владельцами был хотя бы один ревьювер-владелец: жадно берётся владелец,
+// закрывающий больше всего ещё не покрытых путей, при равенстве — случайный из них
+// (по зерну решения; очередь round_robin не сдвигается).
+// Владельцы ищутся среди доступных участников команды PR и её запасных команд;
+// пути, все владельцы которых недоступны, остаются без владельца.
+func (s *PRService) pickCodeOwners(
+	ctx context.Context,
+	pools *reviewerPools,
+	d *decision,
+	team domain.TeamName,
+	files []string,
+	exclude []domain.UserID,
+) ([]domain.ReviewerAssignment, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	matcher, err := pools.matcher(ctx, team)
+	if err != nil || matcher == nil {
+		return nil, err
+	}
+
+	// владельцы каждого пути
+	owners := make(map[string]map[domain.UserID]struct{})
+	var uncovered []string
+	for _, f := range files {
+		ids := matcher.OwnersFor(f)
+		if len(ids) == 0 {
+			continue
+		}
+		set := make(map[domain.UserID]struct{}, len(ids))
+		for _, id := range ids {
+			set[id] = struct{}{}
+		}
+		owners[f] = set
+		uncovered = append(uncovered, f)
+	}
+	if len(uncovered) == 0 {
+		return nil, nil
+	}
+
+	// доступные кандидаты-владельцы и команды, из пулов которых они взяты
+	chain, err := pools.chain(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []domain.Candidate
+	from := make(map[domain.UserID]domain.TeamName)
+	for _, t := range chain {
+		pool, err := pools.pool(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		ok, _ := d.eligible(t, s.selectors.ForTeam(t).Strategy(), pool, exclude)
+		for _, c := range ok {
+			if _, seen := from[c.User.ID]; seen {
+				continue
+			}
+			for _, set := range owners {
+				if _, isOwner := set[c.User.ID]; isOwner {
+					from[c.User.ID] = t
+					candidates = append(candidates, c)
+					break
+				}
+			}
+		}
+	}
+
+	var picked []domain.ReviewerAssignment
+	for len(uncovered) > 0 && len(candidates) > 0 {
+		best, bestScore := []domain.Candidate(nil), 0
+		for _, c := range candidates {
+			score := 0
+			for _, f := range uncovered {
+				if _, ok := owners[f][c.User.ID]; ok {
+					score++
+				}
+			}
+			switch {
+			case score > bestScore:
+				best, bestScore = []domain.Candidate{c}, score
+			case score == bestScore && score > 0:
+				best = append(best, c)
+			}
+		}
+		if bestScore == 0 {
+			break
+		}
+
+		id := best[d.rnd.Intn(len(best))].User.ID
+
+		a := domain.ReviewerAssignment{ReviewerID: id, Reason: domain.AssignmentCodeOwner, Team: from[id]}
+		rest := uncovered[:0]
+		for _, f := range uncovered {
+			if _, ok := owners[f][id]; ok {
+				a.Paths = append(a.Paths, f)
+			} else {
+				rest = append(rest, f)
+			}
+		}
+		uncovered = rest
+		picked = append(picked, a)
+
+		remaining := candidates[:0]
+		for _, c := range candidates {
+			if c.User.ID != id {
+				remaining = append(remaining, c)
+			}
+		}
+		candidates = remaining
+	}
+	return picked, nil
+}
+
+// classify делит пул на кандидатов и отсеянных с причиной: автор, уже назначенные (exclude),
+// неактивные, недоступные и достигшие лимита открытых ревью
+func classify(
+	pool []domain.Candidate,
+	author domain.UserID,
+	exclude []domain.UserID,
+) ([]domain.Candidate, []domain.ExcludedCandidate) {
+	skip := make(map[domain.UserID]struct{}, len(exclude))
+	for _, id := range exclude {
+		skip[id] = struct{}{}
+	}
+
+	candidates := make([]domain.Candidate, 0, len(pool))
+	var excluded []domain.ExcludedCandidate
+	for _, c := range pool {
+		var reason domain.ExclusionReason
+		_, assigned := skip[c.User.ID]
+		switch {
+		case c.User.ID == author:
+			reason = domain.ExcludedAuthor
+		case assigned:
+			reason = domain.ExcludedAlreadyAssigned
+		case !c.User.IsActive:
+			reason = domain.ExcludedInactive
+		case c.Unavailable:
+			reason = domain.ExcludedUnavailable
+		case c.AtCapacity():
+			reason = domain.ExcludedAtCapacity
+		default:
+			candidates = append(candidates, c)
+			continue
+		}
+		excluded = append(excluded, domain.ExcludedCandidate{UserID: c.User.ID, Reason: reason})
+	}
+	return candidates, excluded
+}
@@ -0,0 +1,28 @@
+package selector
+
+import (
+	"math/rand"
+	"sort"
+
+	"prservice/internal/domain"
+)
+
+// LeastLoaded — в первую очередь кандидаты с наименьшим числом открытых ревью,
+// при равной нагрузке выбор случайный
+type LeastLoaded struct{}
+
+func NewLeastLoaded() *LeastLoaded {
+	return &LeastLoaded{}
+}
+
+func (LeastLoaded) Select(rnd *rand.Rand, _ domain.TeamName, candidates []domain.Candidate, n int) []domain.Candidate {
+	res := shuffled(rnd, candidates)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].OpenReviews < res[j].OpenReviews
+	})
+	return firstN(res, n)
+}
+
+func (LeastLoaded) Strategy() domain.ReviewerStrategy {
+	return domain.StrategyLeastLoaded
+}
@@ -0,0 +1,22 @@
+package selector
+
+import (
+	"math/rand"
+
+	"prservice/internal/domain"
+)
+
+// Random — равновероятный выбор среди кандидатов
+type Random struct{}
+
+func NewRandom() *Random {
+	return &Random{}
+}
+
+func (Random) Select(rnd *rand.Rand, _ domain.TeamName, candidates []domain.Candidate, n int) []domain.Candidate {
+	return firstN(shuffled(rnd, candidates), n)
+}
+
+func (Random) Strategy() domain.ReviewerStrategy {
+	return domain.StrategyRandom
+}
@@ -0,0 +1,75 @@
+package selector
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"prservice/internal/domain"
+)
+
+// RoundRobin — по очереди в порядке user_id.
+// Для каждой команды запоминается последний выбранный пользователь,
+// следующий выбор начинается с идущего за ним.
+type RoundRobin struct {
+	mu   sync.Mutex
+	last map[domain.TeamName]domain.UserID
+}
+
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{last: make(map[domain.TeamName]domain.UserID)}
+}
+
+func (s *RoundRobin) Select(_ *rand.Rand, team domain.TeamName, candidates []domain.Candidate, n int) []domain.Candidate {
+	res, _ := s.SelectAt(team, candidates, n)
+	return res
+}
+
+// SelectAt — как Select; второе значение — последний выбранный в команде до этого выбора
+func (s *RoundRobin) SelectAt(team domain.TeamName, candidates []domain.Candidate, n int) ([]domain.Candidate, domain.UserID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.last[team]
+	if len(candidates) == 0 || n <= 0 {
+		return nil, last
+	}
+
+	ordered := make([]domain.Candidate, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].User.ID < ordered[j].User.ID
+	})
+
+	// первый кандидат после последнего выбранного (по кругу)
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].User.ID > last
+	})
+
+	if n > len(ordered) {
+		n = len(ordered)
+	}
+	res := make([]domain.Candidate, 0, n)
+	for i := 0; i < n; i++ {
+		res = append(res, ordered[(start+i)%len(ordered)])
+	}
+	s.last[team] = res[len(res)-1].User.ID
+	return res, last
+}
+
+// Restore ставит очередь команды на позицию из трассировки: следующий выбор начнётся
+// с кандидата, идущего после position
+func (s *RoundRobin) Restore(team domain.TeamName, position domain.UserID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if position == "" {
+		delete(s.last, team)
+		return
+	}
+	s.last[team] = position
+}
+
+func (s *RoundRobin) Strategy() domain.ReviewerStrategy {
+	return domain.StrategyRoundRobin
+}
@@ -0,0 +1,82 @@
+package selector
+
+import (
+	"fmt"
+	"math/rand"
+
+	"prservice/internal/domain"
+)
+
+// Provider хранит стратегию по умолчанию и переопределения для отдельных команд
+type Provider struct {
+	def    domain.ReviewerSelector
+	byTeam map[domain.TeamName]domain.ReviewerSelector
+}
+
+// NewProvider собирает стратегии из конфигурации.
+// weights используются только стратегией weighted.
+func NewProvider(
+	def domain.ReviewerStrategy,
+	byTeam map[domain.TeamName]domain.ReviewerStrategy,
+	weights map[domain.UserID]int,
+) (*Provider, error) {
+	defSel, err := New(def, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		def:    defSel,
+		byTeam: make(map[domain.TeamName]domain.ReviewerSelector, len(byTeam)),
+	}
+	for team, strategy := range byTeam {
+		sel, err := New(strategy, weights)
+		if err != nil {
+			return nil, fmt.Errorf("team %q: %w", team, err)
+		}
+		p.byTeam[team] = sel
+	}
+	return p, nil
+}
+
+func (p *Provider) ForTeam(team domain.TeamName) domain.ReviewerSelector {
+	if sel, ok := p.byTeam[team]; ok {
+		return sel
+	}
+	return p.def
+}
+
+// New создаёт стратегию по имени
+func New(strategy domain.ReviewerStrategy, weights map[domain.UserID]int) (domain.ReviewerSelector, error) {
+	switch strategy {
+	case domain.StrategyRandom:
+		return NewRandom(), nil
+	case domain.StrategyLeastLoaded:
+		return NewLeastLoaded(), nil
+	case domain.StrategyRoundRobin:
+		return NewRoundRobin(), nil
+	case domain.StrategyWeighted:
+		return NewWeighted(weights), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer strategy %q", strategy)
+	}
+}
+
+func firstN(candidates []domain.Candidate, n int) []domain.Candidate {
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return candidates[:n]
+}
+
+func shuffled(rnd *rand.Rand, candidates []domain.Candidate) []domain.Candidate {
+	res := make([]domain.Candidate, len(candidates))
+	copy(res, candidates)
+	rnd.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+	return res
+}
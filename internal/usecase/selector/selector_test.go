@@ -0,0 +1,234 @@
+package selector
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"prservice/internal/domain"
+)
+
+func candidates(loads map[domain.UserID]int) []domain.Candidate {
+	res := make([]domain.Candidate, 0, len(loads))
+	for id, load := range loads {
+		res = append(res, domain.Candidate{User: domain.User{ID: id, IsActive: true}, OpenReviews: load})
+	}
+	slices.SortFunc(res, func(a, b domain.Candidate) int {
+		switch {
+		case a.User.ID < b.User.ID:
+			return -1
+		case a.User.ID > b.User.ID:
+			return 1
+		}
+		return 0
+	})
+	return res
+}
+
+func ids(cs []domain.Candidate) []domain.UserID {
+	res := make([]domain.UserID, len(cs))
+	for i, c := range cs {
+		res[i] = c.User.ID
+	}
+	return res
+}
+
+// picked — сколько раз каждый пользователь выбран за seeds решений
+func picked(sel domain.ReviewerSelector, cs []domain.Candidate, n, seeds int) map[domain.UserID]int {
+	res := make(map[domain.UserID]int)
+	for seed := 0; seed < seeds; seed++ {
+		for _, id := range ids(sel.Select(rand.New(rand.NewSource(int64(seed))), "backend", cs, n)) {
+			res[id]++
+		}
+	}
+	return res
+}
+
+func TestLeastLoaded(t *testing.T) {
+	tests := []struct {
+		name  string
+		loads map[domain.UserID]int
+		n     int
+		// always — выбираются при любом зерне; sometimes — только при некоторых (равная нагрузка)
+		always    []domain.UserID
+		sometimes []domain.UserID
+	}{
+		{
+			name:   "lowest load first",
+			loads:  map[domain.UserID]int{"u1": 3, "u2": 0, "u3": 1},
+			n:      2,
+			always: []domain.UserID{"u2", "u3"},
+		},
+		{
+			name:      "tie broken by seed",
+			loads:     map[domain.UserID]int{"u1": 1, "u2": 1, "u3": 5},
+			n:         1,
+			sometimes: []domain.UserID{"u1", "u2"},
+		},
+		{
+			name:      "tie after the least loaded",
+			loads:     map[domain.UserID]int{"u1": 0, "u2": 2, "u3": 2, "u4": 7},
+			n:         2,
+			always:    []domain.UserID{"u1"},
+			sometimes: []domain.UserID{"u2", "u3"},
+		},
+		{
+			name:   "n above candidates",
+			loads:  map[domain.UserID]int{"u1": 4, "u2": 2},
+			n:      5,
+			always: []domain.UserID{"u1", "u2"},
+		},
+	}
+
+	const seeds = 100
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := picked(NewLeastLoaded(), candidates(tt.loads), tt.n, seeds)
+			for id, count := range got {
+				switch {
+				case slices.Contains(tt.always, id):
+					if count != seeds {
+						t.Errorf("%s picked %d/%d times, want always", id, count, seeds)
+					}
+				case slices.Contains(tt.sometimes, id):
+					if count == 0 || count == seeds {
+						t.Errorf("%s picked %d/%d times, want the tie broken both ways", id, count, seeds)
+					}
+				default:
+					t.Errorf("%s picked %d times, want never", id, count)
+				}
+			}
+			for _, id := range slices.Concat(tt.always, tt.sometimes) {
+				if got[id] == 0 {
+					t.Errorf("%s never picked", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	all := []domain.UserID{"u1", "u2", "u3", "u4"}
+
+	tests := []struct {
+		name string
+		// position — последний выбранный до этого выбора
+		position   domain.UserID
+		candidates []domain.UserID
+		n          int
+		want       []domain.UserID
+	}{
+		{name: "empty queue starts from the first", candidates: all, n: 2, want: []domain.UserID{"u1", "u2"}},
+		{name: "continues after position", position: "u1", candidates: all, n: 2, want: []domain.UserID{"u2", "u3"}},
+		{name: "wraps around", position: "u3", candidates: all, n: 3, want: []domain.UserID{"u4", "u1", "u2"}},
+		{name: "wraps from the last", position: "u4", candidates: all, n: 1, want: []domain.UserID{"u1"}},
+		{
+			name:       "skips excluded position",
+			position:   "u2",
+			candidates: []domain.UserID{"u1", "u3", "u4"},
+			n:          1,
+			want:       []domain.UserID{"u3"},
+		},
+		{
+			name:       "skips excluded after position",
+			position:   "u1",
+			candidates: []domain.UserID{"u1", "u4"},
+			n:          2,
+			want:       []domain.UserID{"u4", "u1"},
+		},
+		{name: "n above candidates", position: "u3", candidates: all[:3], n: 5, want: []domain.UserID{"u1", "u2", "u3"}},
+		{name: "no candidates", position: "u1", n: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := make([]domain.Candidate, len(tt.candidates))
+			for i, id := range tt.candidates {
+				cs[i] = domain.Candidate{User: domain.User{ID: id}}
+			}
+			// порядок кандидатов на входе не важен
+			slices.Reverse(cs)
+
+			rr := NewRoundRobin()
+			rr.Restore("backend", tt.position)
+			got, position := rr.SelectAt("backend", cs, tt.n)
+			if !slices.Equal(ids(got), tt.want) {
+				t.Fatalf("got %v, want %v", ids(got), tt.want)
+			}
+			if position != tt.position {
+				t.Errorf("position = %q, want %q", position, tt.position)
+			}
+
+			// очереди команд независимы
+			if other := rr.Select(nil, "frontend", cs, 1); len(cs) > 0 && other[0].User.ID != cs[len(cs)-1].User.ID {
+				t.Errorf("frontend queue affected: got %v", ids(other))
+			}
+		})
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	loads := map[domain.UserID]int{"u1": 0, "u2": 0, "u3": 0}
+
+	tests := []struct {
+		name    string
+		weights map[domain.UserID]int
+		// heavy — выбирается чаще остальных
+		heavy domain.UserID
+	}{
+		{name: "no weights", weights: nil},
+		{name: "absent weights default to one", weights: map[domain.UserID]int{"u1": 1}},
+		{name: "zero weight counts as one", weights: map[domain.UserID]int{"u1": 0, "u2": 0}},
+		{name: "negative weight counts as one", weights: map[domain.UserID]int{"u3": -5}},
+		{name: "heavy against absent", weights: map[domain.UserID]int{"u2": 20}, heavy: "u2"},
+		{name: "heavy against zero", weights: map[domain.UserID]int{"u1": 0, "u3": 20}, heavy: "u3"},
+	}
+
+	const seeds = 300
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := picked(NewWeighted(tt.weights), candidates(loads), 1, seeds)
+			for id := range loads {
+				if got[id] == 0 {
+					t.Errorf("%s never picked: %v", id, got)
+				}
+				if tt.heavy != "" && id != tt.heavy && got[id] >= got[tt.heavy] {
+					t.Errorf("%s picked %d times, heavy %s only %d", id, got[id], tt.heavy, got[tt.heavy])
+				}
+			}
+		})
+	}
+}
+
+func TestProvider(t *testing.T) {
+	p, err := NewProvider(
+		domain.StrategyLeastLoaded,
+		map[domain.TeamName]domain.ReviewerStrategy{"docs": domain.StrategyRoundRobin, "ml": domain.StrategyWeighted},
+		map[domain.UserID]int{"u1": 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		team domain.TeamName
+		want domain.ReviewerStrategy
+	}{
+		{"docs", domain.StrategyRoundRobin},
+		{"ml", domain.StrategyWeighted},
+		{"backend", domain.StrategyLeastLoaded},
+		{"", domain.StrategyLeastLoaded},
+	}
+	for _, tt := range tests {
+		if got := p.ForTeam(tt.team).Strategy(); got != tt.want {
+			t.Errorf("ForTeam(%q) = %s, want %s", tt.team, got, tt.want)
+		}
+	}
+
+	if _, err := NewProvider(domain.StrategyRandom, map[domain.TeamName]domain.ReviewerStrategy{"docs": "fastest"}, nil); err == nil {
+		t.Error("unknown team strategy: expected error")
+	}
+	if _, err := NewProvider("fastest", nil, nil); err == nil {
+		t.Error("unknown default strategy: expected error")
+	}
+}
@@ -0,0 +1,51 @@
+package selector
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"prservice/internal/domain"
+)
+
+// Weighted — случайный выбор без повторов с вероятностью, пропорциональной весу.
+// Пользователи без явно заданного веса получают вес 1.
+type Weighted struct {
+	weights map[domain.UserID]int
+}
+
+func NewWeighted(weights map[domain.UserID]int) *Weighted {
+	return &Weighted{weights: weights}
+}
+
+func (s *Weighted) Select(rnd *rand.Rand, _ domain.TeamName, candidates []domain.Candidate, n int) []domain.Candidate {
+	// алгоритм Efraimidis–Spirakis: ключ u^(1/w), берём n наибольших
+	type keyed struct {
+		c   domain.Candidate
+		key float64
+	}
+	items := make([]keyed, 0, len(candidates))
+	for _, c := range candidates {
+		items = append(items, keyed{c: c, key: math.Pow(rnd.Float64(), 1/float64(s.weight(c.User.ID)))})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+
+	res := make([]domain.Candidate, 0, len(items))
+	for _, it := range items {
+		res = append(res, it.c)
+	}
+	return firstN(res, n)
+}
+
+func (s *Weighted) Strategy() domain.ReviewerStrategy {
+	return domain.StrategyWeighted
+}
+
+func (s *Weighted) weight(id domain.UserID) int {
+	if w, ok := s.weights[id]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
@@ -2,39 +2,52 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"prservice/internal/domain"
 )
 
 type TeamService struct {
-	teams domain.TeamRepository
-	users domain.UserRepository
+	teams   domain.TeamRepository
+	users   domain.UserRepository
+	prs     domain.PRRepository
+	reviews *PRService
+	clock   Clock
 }
 
-func NewTeamService(teams domain.TeamRepository, users domain.UserRepository) *TeamService {
-	return &TeamService{teams: teams, users: users}
+func NewTeamService(
+	teams domain.TeamRepository,
+	users domain.UserRepository,
+	prs domain.PRRepository,
+	reviews *PRService,
+	clock Clock,
+) *TeamService {
+	return &TeamService{teams: teams, users: users, prs: prs, reviews: reviews, clock: clock}
 }
 
 func (s *TeamService) AddTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	if err := team.Settings.Validate(); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.teams.GetTeam(ctx, team.Name)
 	if err == nil && existing != nil {
 		return nil, domain.ErrTeamExists
 	}
 
-	if err := s.teams.CreateTeam(ctx, team); err != nil {
-		return nil, err
-	}
-
-	for _, m := range team.Members {
-		u := domain.User{
-			ID:       m.UserID,
-			Username: m.Username,
-			TeamName: team.Name,
-			IsActive: m.IsActive,
+	// команда, участники и событие TeamCreated — одной транзакцией
+	err = s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		if err := tx.CreateTeam(ctx, team); err != nil {
+			return err
 		}
-		if err := s.users.UpsertUser(ctx, u); err != nil {
-			return nil, err
+		if err := upsertMembers(ctx, tx, team.Name, team.Members); err != nil {
+			return err
 		}
+		return tx.SaveEvents(ctx, []domain.Event{domain.NewTeamCreatedEvent(team, s.clock.Now())})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	res, err := s.teams.GetTeam(ctx, team.Name)
@@ -47,13 +60,364 @@ func (s *TeamService) AddTeam(ctx context.Context, team domain.Team) (*domain.Te
 	return res, nil
 }
 
+// GetTeam — команда с участниками; архивная команда считается ненайденной
 func (s *TeamService) GetTeam(ctx context.Context, name domain.TeamName) (*domain.Team, error) {
 	team, err := s.teams.GetTeam(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	if team == nil {
+	if team == nil || team.ArchivedAt != nil {
 		return nil, domain.ErrNotFound
 	}
 	return team, nil
 }
+
+// DeleteTeam удаляет команду. В режиме archive команда скрывается и перестаёт
+// участвовать в назначении, история сохраняется. В режиме hard команда удаляется,
+// участники остаются без команды; удаление запрещено, пока у участников есть OPEN PR
+// (авторские или на ревью). Жёстко удалить можно и архивную команду.
+func (s *TeamService) DeleteTeam(ctx context.Context, name domain.TeamName, mode domain.TeamDeleteMode) error {
+	if err := mode.Validate(); err != nil {
+		return err
+	}
+
+	team, err := s.teams.GetTeam(ctx, name)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return domain.ErrNotFound
+	}
+
+	if mode == domain.TeamDeleteArchive {
+		if team.ArchivedAt != nil {
+			return domain.ErrNotFound
+		}
+		ok, err := s.teams.ArchiveTeam(ctx, name, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrNotFound
+		}
+		return nil
+	}
+
+	return s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		open, err := tx.CountOpenPRsByTeam(ctx, name)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return fmt.Errorf("%w: %d", domain.ErrTeamHasOpenPRs, open)
+		}
+
+		ok, err := tx.DeleteTeam(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, name domain.TeamName) (*domain.TeamSettings, error) {
+	settings, err := s.teams.GetSettings(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, domain.ErrNotFound
+	}
+	return settings, nil
+}
+
+func (s *TeamService) UpdateSettings(
+	ctx context.Context,
+	name domain.TeamName,
+	settings domain.TeamSettings,
+) (*domain.TeamSettings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := s.teams.UpdateSettings(ctx, name, settings)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+// SetFallbacks задаёт запасные команды в порядке приоритета: из них добираются
+// ревьюверы, если участников команды не хватает. Пустой список отключает запасные команды.
+func (s *TeamService) SetFallbacks(
+	ctx context.Context,
+	name domain.TeamName,
+	fallbacks []domain.TeamName,
+) (*domain.Team, error) {
+	if _, err := s.GetTeam(ctx, name); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[domain.TeamName]struct{}, len(fallbacks))
+	for _, f := range fallbacks {
+		if _, ok := seen[f]; ok || f == name {
+			return nil, domain.ErrInvalidFallbacks
+		}
+		seen[f] = struct{}{}
+		if _, err := s.GetTeam(ctx, f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.teams.SetFallbacks(ctx, name, fallbacks); err != nil {
+		return nil, err
+	}
+	return s.GetTeam(ctx, name)
+}
+
+func (s *TeamService) GetCodeOwners(ctx context.Context, name domain.TeamName) (*domain.CodeOwners, error) {
+	if _, err := s.GetTeam(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.teams.GetCodeOwners(ctx, name)
+}
+
+// SetCodeOwners заменяет правила владения кодом команды. Все упомянутые пользователи
+// должны существовать; состоять в команде не обязательно, но назначаются владельцы только
+// из участников команды PR и её запасных команд.
+func (s *TeamService) SetCodeOwners(
+	ctx context.Context,
+	name domain.TeamName,
+	owners domain.CodeOwners,
+) (*domain.CodeOwners, error) {
+	if err := owners.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTeam(ctx, name); err != nil {
+		return nil, err
+	}
+
+	var ids []domain.UserID
+	for _, g := range owners.Groups {
+		ids = append(ids, g.Members...)
+	}
+	for _, r := range owners.Rules {
+		for _, o := range r.Owners {
+			if !strings.HasPrefix(o, "@") {
+				ids = append(ids, domain.UserID(o))
+			}
+		}
+	}
+	for _, id := range ids {
+		if _, err := s.getUser(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.teams.SetCodeOwners(ctx, name, owners); err != nil {
+		return nil, err
+	}
+	return s.teams.GetCodeOwners(ctx, name)
+}
+
+func (s *TeamService) GetReviewSLA(ctx context.Context, name domain.TeamName) (*domain.ReviewSLA, error) {
+	if _, err := s.GetTeam(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.teams.GetReviewSLA(ctx, name)
+}
+
+// SetReviewSLA задаёт срок первого ревью и политику для просроченных ревью.
+// Лид должен существовать; состоять в команде не обязательно. Срок 0 отключает SLA.
+func (s *TeamService) SetReviewSLA(
+	ctx context.Context,
+	name domain.TeamName,
+	sla domain.ReviewSLA,
+) (*domain.ReviewSLA, error) {
+	if sla.Policy == "" {
+		sla.Policy = domain.DefaultSLAPolicy
+	}
+	if err := sla.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTeam(ctx, name); err != nil {
+		return nil, err
+	}
+	if sla.LeadID != "" {
+		if _, err := s.getUser(ctx, sla.LeadID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.teams.SetReviewSLA(ctx, name, sla); err != nil {
+		return nil, err
+	}
+	return s.teams.GetReviewSLA(ctx, name)
+}
+
+// AddMembers добавляет участников в существующую команду. Пользователь может
+// состоять в нескольких командах: членство в других командах сохраняется.
+func (s *TeamService) AddMembers(
+	ctx context.Context,
+	name domain.TeamName,
+	members []domain.TeamMember,
+) (*domain.Team, error) {
+	team, err := s.GetTeam(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return team, nil
+	}
+
+	err = s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		return upsertMembers(ctx, tx, name, members)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(ctx, name)
+}
+
+// RemoveMember исключает пользователя из команды. Открытые ревью на PR этой команды
+// остаются за ним или переназначаются на её участников — в зависимости от policy.
+func (s *TeamService) RemoveMember(
+	ctx context.Context,
+	name domain.TeamName,
+	userID domain.UserID,
+	policy domain.OpenReviewsPolicy,
+) (*domain.User, *domain.ReassignmentReport, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.getUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.leaveTeam(ctx, userID, name, policy, nil)
+}
+
+// MoveMember переводит пользователя из команды from (пусто — из основной) в команду to.
+// Если from была основной, основной становится to. Открытые ревью на PR команды from
+// остаются за ним или переназначаются на её участников — в зависимости от policy.
+func (s *TeamService) MoveMember(
+	ctx context.Context,
+	userID domain.UserID,
+	from, to domain.TeamName,
+	policy domain.OpenReviewsPolicy,
+) (*domain.User, *domain.ReassignmentReport, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, nil, err
+	}
+	old, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.GetTeam(ctx, to); err != nil {
+		return nil, nil, err
+	}
+
+	if from == "" {
+		from = old.TeamName
+	}
+	if from == "" {
+		return nil, nil, domain.ErrNotTeamMember
+	}
+	if from == to {
+		return old, &domain.ReassignmentReport{}, nil
+	}
+
+	return s.leaveTeam(ctx, userID, from, policy, func(tx domain.PRTx, u *domain.User) (*domain.User, error) {
+		if err := tx.AddTeamMember(ctx, to, userID); err != nil {
+			return nil, err
+		}
+		if old.TeamName != from {
+			return u, nil
+		}
+		return tx.SetUserTeam(ctx, userID, to)
+	})
+}
+
+// leaveTeam в одной транзакции исключает пользователя из команды team, выполняет then
+// (если задана, например добавление в новую команду) и применяет policy к его ревью на PR команды team
+func (s *TeamService) leaveTeam(
+	ctx context.Context,
+	userID domain.UserID,
+	team domain.TeamName,
+	policy domain.OpenReviewsPolicy,
+	then func(tx domain.PRTx, u *domain.User) (*domain.User, error),
+) (*domain.User, *domain.ReassignmentReport, error) {
+	var (
+		user   *domain.User
+		report = &domain.ReassignmentReport{}
+	)
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		u, err := tx.RemoveTeamMember(ctx, team, userID)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			return domain.ErrNotTeamMember
+		}
+
+		if then != nil {
+			if u, err = then(tx, u); err != nil {
+				return err
+			}
+		}
+		user = u
+
+		if policy != domain.OpenReviewsReassign {
+			return nil
+		}
+		rep, err := s.reviews.ReassignOpenReviews(ctx, tx, []domain.User{*u}, team)
+		if err != nil {
+			return err
+		}
+		report = rep
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, report, nil
+}
+
+func (s *TeamService) getUser(ctx context.Context, id domain.UserID) (*domain.User, error) {
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+// upsertMembers создаёт или обновляет пользователей и добавляет их в команду;
+// для пользователей без основной команды она становится основной
+func upsertMembers(ctx context.Context, tx domain.PRTx, name domain.TeamName, members []domain.TeamMember) error {
+	for _, m := range members {
+		u := domain.User{
+			ID:       m.UserID,
+			Username: m.Username,
+			TeamName: name,
+			IsActive: m.IsActive,
+		}
+		if err := tx.UpsertUser(ctx, u); err != nil {
+			return err
+		}
+		if err := tx.AddTeamMember(ctx, name, m.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,15 +7,197 @@ import (
 )
 
 type UserService struct {
-	users domain.UserRepository
+	users   domain.UserRepository
+	teams   domain.TeamRepository
+	prs     domain.PRRepository
+	reviews *PRService
+	clock   Clock
 }
 
-func NewUserService(users domain.UserRepository) *UserService {
-	return &UserService{users: users}
+func NewUserService(
+	users domain.UserRepository,
+	teams domain.TeamRepository,
+	prs domain.PRRepository,
+	reviews *PRService,
+	clock Clock,
+) *UserService {
+	return &UserService{users: users, teams: teams, prs: prs, reviews: reviews, clock: clock}
 }
 
+// SetIsActive меняет флаг активности; деактивация записывает событие UserDeactivated
 func (s *UserService) SetIsActive(ctx context.Context, id domain.UserID, active bool) (*domain.User, error) {
-	u, err := s.users.SetIsActive(ctx, id, active)
+	if active {
+		u, err := s.users.SetIsActive(ctx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil {
+			return nil, domain.ErrNotFound
+		}
+		return u, nil
+	}
+
+	var user *domain.User
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		u, err := tx.SetUserIsActive(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			return domain.ErrNotFound
+		}
+		user = u
+		return tx.SaveEvents(ctx, s.deactivatedEvents([]domain.User{*u}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeactivateAndReassign деактивирует пользователя и в той же транзакции
+// переназначает все его открытые ревью на других участников команд этих PR
+func (s *UserService) DeactivateAndReassign(
+	ctx context.Context,
+	id domain.UserID,
+) (*domain.User, *domain.ReassignmentReport, error) {
+	var (
+		user   *domain.User
+		report *domain.ReassignmentReport
+	)
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		u, err := tx.SetUserIsActive(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			return domain.ErrNotFound
+		}
+
+		rep, err := s.reviews.ReassignOpenReviews(ctx, tx, []domain.User{*u}, "")
+		if err != nil {
+			return err
+		}
+		user, report = u, rep
+		return tx.SaveEvents(ctx, s.deactivatedEvents([]domain.User{*u}))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, report, nil
+}
+
+// BulkDeactivate деактивирует всех участников команды team (если задана) и пользователей ids,
+// переназначая их открытые ревью на оставшихся активных участников. Всё — одной транзакцией:
+// если хотя бы один пользователь не найден, ничего не меняется.
+func (s *UserService) BulkDeactivate(
+	ctx context.Context,
+	team domain.TeamName,
+	ids []domain.UserID,
+) ([]domain.User, *domain.ReassignmentReport, error) {
+	targets := make([]domain.UserID, 0, len(ids))
+	seen := make(map[domain.UserID]struct{}, len(ids))
+	add := func(id domain.UserID) {
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		targets = append(targets, id)
+	}
+
+	if team != "" {
+		t, err := s.teams.GetTeam(ctx, team)
+		if err != nil {
+			return nil, nil, err
+		}
+		if t == nil {
+			return nil, nil, domain.ErrNotFound
+		}
+		for _, m := range t.Members {
+			add(m.UserID)
+		}
+	}
+	for _, id := range ids {
+		add(id)
+	}
+	if team == "" && len(targets) == 0 {
+		return nil, nil, domain.ErrEmptyBulkRequest
+	}
+
+	var (
+		users  []domain.User
+		report *domain.ReassignmentReport
+	)
+
+	err := s.prs.WithTx(ctx, func(tx domain.PRTx) error {
+		deactivated, err := tx.DeactivateUsers(ctx, targets)
+		if err != nil {
+			return err
+		}
+		if len(deactivated) != len(targets) {
+			return domain.ErrNotFound
+		}
+
+		rep, err := s.reviews.ReassignOpenReviews(ctx, tx, deactivated, "")
+		if err != nil {
+			return err
+		}
+		users, report = deactivated, rep
+		return tx.SaveEvents(ctx, s.deactivatedEvents(deactivated))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, report, nil
+}
+
+// SetMaxOpenReviews задаёт лимит открытых ревью, nil снимает лимит
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, id domain.UserID, limit *int) (*domain.User, error) {
+	if limit != nil && *limit < 0 {
+		return nil, domain.ErrInvalidCapacity
+	}
+
+	u, err := s.users.SetMaxOpenReviews(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *UserService) AddUnavailability(ctx context.Context, u domain.Unavailability) (*domain.Unavailability, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(ctx, u.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return s.users.AddUnavailability(ctx, u)
+}
+
+func (s *UserService) ListUnavailability(ctx context.Context, id domain.UserID) ([]domain.Unavailability, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return s.users.ListUnavailability(ctx, id)
+}
+
+func (s *UserService) DeleteUnavailability(ctx context.Context, id domain.UnavailabilityID) (*domain.Unavailability, error) {
+	u, err := s.users.DeleteUnavailability(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -24,3 +206,14 @@ func (s *UserService) SetIsActive(ctx context.Context, id domain.UserID, active
 	}
 	return u, nil
 }
+
+// deactivatedEvents — UserDeactivated на каждого пользователя (в том числе уже неактивного:
+// повторная деактивация — тоже явное действие)
+func (s *UserService) deactivatedEvents(users []domain.User) []domain.Event {
+	now := s.clock.Now()
+	events := make([]domain.Event, 0, len(users))
+	for _, u := range users {
+		events = append(events, domain.NewUserDeactivatedEvent(u, now))
+	}
+	return events
+}
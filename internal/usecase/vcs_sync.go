@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"prservice/internal/domain"
+)
+
+// VCSSyncService — синхронизация PR с событиями GitHub/GitLab через PRService.
+// Доставка вебхуков — не менее одного раза, поэтому каждое действие идемпотентно:
+// повтор события, уже применённого к PR, возвращает PR без изменений.
+type VCSSyncService struct {
+	prs        *PRService
+	identities domain.IdentityMap
+}
+
+func NewVCSSyncService(prs *PRService, identities domain.IdentityMap) *VCSSyncService {
+	return &VCSSyncService{prs: prs, identities: identities}
+}
+
+// Apply применяет событие к PR. Возвращает nil без ошибки, если событие не к чему применить:
+// закрыт или влит PR, которого в сервисе нет.
+func (s *VCSSyncService) Apply(ctx context.Context, e domain.VCSPullRequestEvent) (*domain.PullRequest, error) {
+	id := e.PullRequestID()
+
+	pr, err := s.prs.GetPR(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if pr == nil {
+		switch e.Action {
+		case domain.VCSOpened, domain.VCSReadyForReview, domain.VCSReopened:
+			return s.create(ctx, e)
+		default:
+			return nil, nil
+		}
+	}
+
+	switch e.Action {
+	case domain.VCSReadyForReview:
+		if pr.Status == domain.PRStatusDraft {
+			return s.transition(ctx, id, domain.PRStatusOpen, s.prs.MarkReady)
+		}
+	case domain.VCSReopened:
+		if pr.Status == domain.PRStatusClosed {
+			return s.transition(ctx, id, domain.PRStatusOpen, s.prs.Reopen)
+		}
+	case domain.VCSClosed:
+		if pr.Status == domain.PRStatusDraft || pr.Status == domain.PRStatusOpen {
+			return s.transition(ctx, id, domain.PRStatusClosed, s.prs.Close)
+		}
+	case domain.VCSMerged:
+		if pr.Status != domain.PRStatusMerged {
+			// PR уже влит во внешней системе — кворум одобрений сервиса не проверяем
+			return s.prs.Merge(ctx, id, true)
+		}
+	}
+	return pr, nil
+}
+
+// create создаёт PR от имени автора (или владельца репозитория, если автор не сопоставлен)
+func (s *VCSSyncService) create(ctx context.Context, e domain.VCSPullRequestEvent) (*domain.PullRequest, error) {
+	author, ok := s.identities.Resolve(e.Provider, e.Author)
+	if !ok {
+		if author, ok = s.identities.Resolve(e.Provider, e.Owner); !ok {
+			return nil, domain.ErrUnknownIdentity
+		}
+	}
+
+	pr := domain.PullRequest{ID: e.PullRequestID(), Name: e.Title, AuthorID: author}
+	if e.Draft && e.Action == domain.VCSOpened {
+		pr.Status = domain.PRStatusDraft
+	}
+
+	res, err := s.prs.CreatePR(ctx, pr, nil)
+	if errors.Is(err, domain.ErrPRExists) {
+		// PR создан параллельной доставкой того же события
+		return s.prs.GetPR(ctx, pr.ID)
+	}
+	return res, err
+}
+
+// transition выполняет переход; если параллельная доставка уже перевела PR в to, возвращает PR как есть
+func (s *VCSSyncService) transition(
+	ctx context.Context,
+	id domain.PullRequestID,
+	to domain.PRStatus,
+	apply func(ctx context.Context, id domain.PullRequestID) (*domain.PullRequest, error),
+) (*domain.PullRequest, error) {
+	res, err := apply(ctx, id)
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		return res, err
+	}
+
+	pr, getErr := s.prs.GetPR(ctx, id)
+	if getErr != nil {
+		return nil, getErr
+	}
+	if pr.Status != to {
+		return nil, err
+	}
+	return pr, nil
+}
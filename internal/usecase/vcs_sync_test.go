@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"prservice/internal/adapter/repo/memory"
+	"prservice/internal/domain"
+	"prservice/internal/usecase/selector"
+)
+
+func newTestVCSSync(t *testing.T) (*VCSSyncService, *PRService) {
+	t.Helper()
+	return newTestVCSSyncWith(t, func(prs domain.PRRepository) domain.PRRepository { return prs })
+}
+
+// newTestVCSSyncWith — как newTestVCSSync, но хранилище PR оборачивается wrap
+func newTestVCSSyncWith(t *testing.T, wrap func(domain.PRRepository) domain.PRRepository) (*VCSSyncService, *PRService) {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.New()
+	teams, users := memory.NewTeamRepo(db), memory.NewUserRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range teamUsers("u1", "u2", "u3") {
+		if err := users.UpsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := teams.AddMember(ctx, "backend", u.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	selectors, err := selector.NewProvider(domain.StrategyRandom, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prs := NewPRService(wrap(memory.NewPRRepo(db)), users, teams, selectors, rand.NewSource(1),
+		fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)})
+	identities := domain.NewIdentityMap(map[string]string{"github:OctoCat": "u1", "github:acme": "u2"})
+	return NewVCSSyncService(prs, identities), prs
+}
+
+func TestVCSSyncIdempotent(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestVCSSync(t)
+
+	opened := domain.VCSPullRequestEvent{
+		Provider:   domain.VCSGitHub,
+		Action:     domain.VCSOpened,
+		Repository: "acme/api",
+		Number:     12,
+		Title:      "Fix login",
+		Draft:      true,
+		Author:     "octocat",
+		Owner:      "acme",
+	}
+	steps := []struct {
+		action domain.VCSAction
+		want   domain.PRStatus
+	}{
+		{domain.VCSOpened, domain.PRStatusDraft},
+		{domain.VCSOpened, domain.PRStatusDraft},
+		{domain.VCSReadyForReview, domain.PRStatusOpen},
+		{domain.VCSReadyForReview, domain.PRStatusOpen},
+		{domain.VCSClosed, domain.PRStatusClosed},
+		{domain.VCSClosed, domain.PRStatusClosed},
+		{domain.VCSReopened, domain.PRStatusOpen},
+		{domain.VCSMerged, domain.PRStatusMerged},
+		{domain.VCSMerged, domain.PRStatusMerged},
+		{domain.VCSClosed, domain.PRStatusMerged},
+	}
+	for i, step := range steps {
+		e := opened
+		e.Action = step.action
+		pr, err := svc.Apply(ctx, e)
+		if err != nil {
+			t.Fatalf("step %d (%s): %v", i, step.action, err)
+		}
+		if pr.ID != "github:acme/api#12" || pr.AuthorID != "u1" || pr.Status != step.want {
+			t.Fatalf("step %d (%s): pr = %s %s by %s, want status %s", i, step.action, pr.ID, pr.Status, pr.AuthorID, step.want)
+		}
+	}
+}
+
+func TestVCSSyncIdentities(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestVCSSync(t)
+
+	// автор не сопоставлен — PR создаётся от имени владельца репозитория
+	e := domain.VCSPullRequestEvent{
+		Provider: domain.VCSGitHub, Action: domain.VCSOpened, Repository: "acme/web", Number: 1,
+		Author: "stranger", Owner: "acme",
+	}
+	pr, err := svc.Apply(ctx, e)
+	if err != nil || pr.AuthorID != "u2" {
+		t.Fatalf("owner fallback: pr = %+v, err = %v", pr, err)
+	}
+
+	e.Owner, e.Number = "other-org", 2
+	if _, err := svc.Apply(ctx, e); !errors.Is(err, domain.ErrUnknownIdentity) {
+		t.Errorf("unmapped: err = %v, want ErrUnknownIdentity", err)
+	}
+
+	// закрытие PR, которого в сервисе нет, игнорируется
+	e.Action = domain.VCSClosed
+	if pr, err := svc.Apply(ctx, e); pr != nil || err != nil {
+		t.Errorf("close unknown: pr = %+v, err = %v", pr, err)
+	}
+}
+
+// racingPRs начинает первые две транзакции только после того, как обе открыты:
+// параллельные доставки успевают пройти проверку существования PR до вставки
+type racingPRs struct {
+	domain.PRRepository
+	started atomic.Int32
+	ready   sync.WaitGroup
+}
+
+func (r *racingPRs) WithTx(ctx context.Context, fn func(tx domain.PRTx) error) error {
+	if r.started.Add(1) <= 2 {
+		r.ready.Done()
+		r.ready.Wait()
+	}
+	return r.PRRepository.WithTx(ctx, fn)
+}
+
+func TestVCSSyncConcurrentDuplicateDelivery(t *testing.T) {
+	ctx := context.Background()
+	racing := &racingPRs{}
+	racing.ready.Add(2)
+	svc, _ := newTestVCSSyncWith(t, func(prs domain.PRRepository) domain.PRRepository {
+		racing.PRRepository = prs
+		return racing
+	})
+
+	e := domain.VCSPullRequestEvent{
+		Provider: domain.VCSGitHub, Action: domain.VCSOpened, Repository: "acme/api", Number: 7,
+		Title: "Fix login", Author: "octocat", Owner: "acme",
+	}
+	var (
+		wg   sync.WaitGroup
+		prs  [2]*domain.PullRequest
+		errs [2]error
+	)
+	for i := range prs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prs[i], errs[i] = svc.Apply(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	for i := range prs {
+		if errs[i] != nil {
+			t.Fatalf("delivery %d: %v", i, errs[i])
+		}
+		if prs[i].ID != "github:acme/api#7" || !slices.Equal(prs[i].AssignedReviewers, prs[0].AssignedReviewers) {
+			t.Fatalf("delivery %d: pr = %+v, want the same PR as delivery 0 (%+v)", i, prs[i], prs[0])
+		}
+	}
+}
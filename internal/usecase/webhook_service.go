@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"prservice/internal/domain"
+)
+
+// WebhookConfig — параметры доставки вебхуков
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease — сколько взятая доставка недоступна другим экземплярам, пока идёт попытка
+	Lease time.Duration
+	// MinBackoff, MaxBackoff — границы экспоненциальной задержки между попытками
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxAttempts — после стольких неудачных попыток доставка переходит в DEAD
+	MaxAttempts int
+}
+
+// WebhookService — подписки команд на доменные события и их доставка.
+// Как domain.EventPublisher ставит опубликованное relay событие в очередь доставки
+// подходящим подписчикам; Run доставляет очередь с повторами.
+type WebhookService struct {
+	webhooks domain.WebhookRepository
+	teams    domain.TeamRepository
+	sender   domain.WebhookSender
+	clock    Clock
+	cfg      WebhookConfig
+}
+
+func NewWebhookService(
+	webhooks domain.WebhookRepository,
+	teams domain.TeamRepository,
+	sender domain.WebhookSender,
+	clock Clock,
+	cfg WebhookConfig,
+) *WebhookService {
+	return &WebhookService{webhooks: webhooks, teams: teams, sender: sender, clock: clock, cfg: cfg}
+}
+
+// CreateSubscription подписывает команду на события; пустой список событий — все события команды
+func (s *WebhookService) CreateSubscription(
+	ctx context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	sub.Events = normalizeEventTypes(sub.Events)
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teams.GetTeam(ctx, sub.Team)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil || team.ArchivedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+
+	sub.CreatedAt = s.clock.Now()
+	return s.webhooks.CreateSubscription(ctx, sub)
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id domain.WebhookID) (*domain.WebhookSubscription, error) {
+	sub, err := s.webhooks.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, domain.ErrNotFound
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) ListSubscriptions(
+	ctx context.Context,
+	team domain.TeamName,
+) ([]domain.WebhookSubscription, error) {
+	return s.webhooks.ListSubscriptions(ctx, team)
+}
+
+// UpdateSubscription меняет URL, фильтр событий и (если задан) секрет; команда подписки не меняется
+func (s *WebhookService) UpdateSubscription(
+	ctx context.Context,
+	sub domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	existing, err := s.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Team = existing.Team
+	if sub.Secret == "" {
+		sub.Secret = existing.Secret
+	}
+	sub.Events = normalizeEventTypes(sub.Events)
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := s.webhooks.UpdateSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id domain.WebhookID) error {
+	ok, err := s.webhooks.DeleteSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeliveries — журнал доставок (по умолчанию DefaultPageSize последних, не больше MaxPageSize)
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	filter domain.WebhookDeliveryFilter,
+) ([]domain.WebhookDelivery, error) {
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultPageSize
+	case filter.Limit < 0 || filter.Limit > MaxPageSize:
+		return nil, domain.ErrInvalidFilter
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, domain.ErrInvalidFilter
+	}
+	if filter.SubscriptionID != 0 {
+		if _, err := s.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+			return nil, err
+		}
+	}
+	return s.webhooks.ListDeliveries(ctx, filter)
+}
+
+// Redeliver возвращает доставку из DEAD в очередь с новым счётчиком попыток
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	d, err := s.webhooks.RequeueDelivery(ctx, id, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, domain.ErrNotFound
+	}
+	return d, nil
+}
+
+// Publish ставит событие в очередь доставки подписчикам его команды
+func (s *WebhookService) Publish(ctx context.Context, e domain.Event) error {
+	if e.Team == "" {
+		return nil
+	}
+
+	subs, err := s.webhooks.ListSubscriptions(ctx, e.Team)
+	if err != nil {
+		return err
+	}
+
+	var ids []domain.WebhookID
+	for _, sub := range subs {
+		if sub.Matches(e) {
+			ids = append(ids, sub.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.webhooks.EnqueueDeliveries(ctx, e, ids, s.clock.Now())
+}
+
+// Run доставляет очередь вебхуков, пока не отменён ctx
+func (s *WebhookService) Run(ctx context.Context) error {
+	poll(ctx, "webhook delivery", s.cfg.PollInterval, s.cfg.BatchSize, s.DeliverOnce)
+	return nil
+}
+
+// DeliverOnce берёт одну пачку доставок и отправляет их; возвращает размер пачки.
+// Неудачная попытка откладывает доставку с экспоненциальной задержкой, после MaxAttempts
+// доставка переходит в DEAD.
+func (s *WebhookService) DeliverOnce(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	deliveries, err := s.webhooks.ClaimDeliveries(ctx, now, now.Add(s.cfg.Lease), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[domain.WebhookID]*domain.WebhookSubscription)
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			return len(deliveries), nil
+		}
+
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			if sub, err = s.webhooks.GetSubscription(ctx, d.SubscriptionID); err != nil {
+				return len(deliveries), err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		if sub == nil {
+			// подписку удалили вместе с журналом, пока доставка была в работе
+			continue
+		}
+
+		status, sendErr := s.sender.Send(ctx, *sub, d)
+		if sendErr != nil && ctx.Err() != nil {
+			return len(deliveries), nil
+		}
+
+		attempt := domain.WebhookAttempt{ResponseStatus: status, At: s.clock.Now()}
+		if sendErr == nil {
+			err = s.webhooks.MarkDelivered(ctx, d.ID, attempt)
+		} else {
+			attempt.Error = sendErr.Error()
+			next := attempt.At.Add(backoff(d.Attempts, s.cfg.MinBackoff, s.cfg.MaxBackoff))
+			err = s.webhooks.MarkFailed(ctx, d.ID, attempt, next, d.Attempts >= s.cfg.MaxAttempts)
+		}
+		if err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// normalizeEventTypes — типы событий без повторов, по алфавиту
+func normalizeEventTypes(types []domain.EventType) []domain.EventType {
+	res := slices.Clone(types)
+	slices.Sort(res)
+	return slices.Compact(res)
+}
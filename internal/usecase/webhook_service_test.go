@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"prservice/internal/adapter/repo/memory"
+	"prservice/internal/adapter/webhook"
+	"prservice/internal/domain"
+)
+
+func TestWebhookDeliveryRetriesAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+
+	// получатель отвечает 500 первые fail запросов
+	var calls, fail atomic.Int32
+	fail.Store(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	db := memory.New()
+	teams := memory.NewTeamRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{now: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)}
+	svc := NewWebhookService(memory.NewWebhookRepo(db), teams, webhook.NewSender(time.Second), clock, WebhookConfig{
+		BatchSize:   10,
+		Lease:       time.Minute,
+		MinBackoff:  time.Second,
+		MaxBackoff:  time.Minute,
+		MaxAttempts: 3,
+	})
+
+	sub, err := svc.CreateSubscription(ctx, domain.WebhookSubscription{
+		Team:   "backend",
+		URL:    srv.URL,
+		Secret: "s3cret",
+		Events: []domain.EventType{domain.EventPRMerged},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// событие не из фильтра подписки в очередь не попадает
+	if err := svc.Publish(ctx, domain.Event{ID: 1, Type: domain.EventPRCreated, Team: "backend"}); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		// повторная публикация (at-least-once) не создаёт второй доставки
+		if err := svc.Publish(ctx, domain.Event{ID: 2, Type: domain.EventPRMerged, Team: "backend"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deliverOnce := func(want int) {
+		t.Helper()
+		n, err := svc.DeliverOnce(ctx)
+		if err != nil || n != want {
+			t.Fatalf("DeliverOnce = %d, %v; want %d", n, err, want)
+		}
+	}
+	lastDelivery := func() domain.WebhookDelivery {
+		t.Helper()
+		list, err := svc.ListDeliveries(ctx, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID})
+		if err != nil || len(list) != 1 {
+			t.Fatalf("ListDeliveries = %v, %v; want one delivery", list, err)
+		}
+		return list[0]
+	}
+
+	// первая попытка неудачна, следующая — через MinBackoff
+	deliverOnce(1)
+	d := lastDelivery()
+	if d.Status != domain.DeliveryPending || d.ResponseStatus != http.StatusInternalServerError || d.LastError == "" {
+		t.Fatalf("after failure: %+v", d)
+	}
+	if want := clock.now.Add(time.Second); !d.NextAttemptAt.Equal(want) {
+		t.Errorf("NextAttemptAt = %v, want %v", d.NextAttemptAt, want)
+	}
+	deliverOnce(0)
+
+	clock.now = clock.now.Add(time.Second)
+	deliverOnce(1)
+	if d = lastDelivery(); d.Status != domain.DeliveryDelivered || d.Attempts != 2 || d.DeliveredAt == nil {
+		t.Fatalf("after retry: %+v", d)
+	}
+
+	// получатель недоступен: после MaxAttempts попыток доставка уходит в DEAD
+	fail.Store(100)
+	if err := svc.Publish(ctx, domain.Event{ID: 3, Type: domain.EventPRMerged, Team: "backend"}); err != nil {
+		t.Fatal(err)
+	}
+	dead := func() []domain.WebhookDelivery {
+		t.Helper()
+		list, err := svc.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: domain.DeliveryDead})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list
+	}
+	for range 3 {
+		deliverOnce(1)
+		clock.now = clock.now.Add(time.Minute)
+	}
+	deliverOnce(0)
+	list := dead()
+	if len(list) != 1 || list[0].Event.ID != 3 || list[0].Attempts != 3 {
+		t.Fatalf("dead deliveries = %+v", list)
+	}
+
+	// ручной повтор возвращает доставку в очередь
+	fail.Store(0)
+	if _, err := svc.Redeliver(ctx, list[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	deliverOnce(1)
+	if len(dead()) != 0 {
+		t.Error("redelivered delivery is still DEAD")
+	}
+	if _, err := svc.Redeliver(ctx, list[0].ID); err != domain.ErrNotFound {
+		t.Errorf("Redeliver of delivered = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateSubscriptionValidation(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	teams := memory.NewTeamRepo(db)
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend", Settings: domain.DefaultTeamSettings()}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewWebhookService(memory.NewWebhookRepo(db), teams, nil, fakeClock{}, WebhookConfig{})
+
+	cases := []struct {
+		name string
+		sub  domain.WebhookSubscription
+		want error
+	}{
+		{"bad url", domain.WebhookSubscription{Team: "backend", URL: "ftp://x", Secret: "s"}, domain.ErrInvalidWebhook},
+		{"no secret", domain.WebhookSubscription{Team: "backend", URL: "http://x"}, domain.ErrInvalidWebhook},
+		{"bad event", domain.WebhookSubscription{
+			Team: "backend", URL: "http://x", Secret: "s", Events: []domain.EventType{"Nope"},
+		}, domain.ErrInvalidWebhook},
+		{"unknown team", domain.WebhookSubscription{Team: "nope", URL: "http://x", Secret: "s"}, domain.ErrNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := svc.CreateSubscription(ctx, tc.sub); err != tc.want {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}